@@ -0,0 +1,481 @@
+package fuzz
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scim2/tools/schema"
+)
+
+// operators are the comparison operators (besides "pr") that apply to the values of an attribute type. The ordering
+// operators do not apply to boolean and binary values, the substring operators only to strings and references.
+var operators = map[schema.Type][]string{
+	schema.StringType:    {"eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le"},
+	schema.ReferenceType: {"eq", "ne", "co", "sw", "ew"},
+	schema.BooleanType:   {"eq", "ne"},
+	schema.BinaryType:    {"eq", "ne"},
+	schema.IntegerType:   {"eq", "ne", "gt", "ge", "lt", "le"},
+	schema.DecimalType:   {"eq", "ne", "gt", "ge", "lt", "le"},
+	schema.DateTimeType:  {"eq", "ne", "gt", "ge", "lt", "le"},
+}
+
+// FilterFuzzer generates filter expressions (RFC 7644, section 3.4.2.2) over the attributes of a schema, e.g.
+// `userName sw "a" and not (emails[type eq "work" or primary eq true])`.
+type FilterFuzzer struct {
+	schema   schema.ReferenceSchema
+	r        *rand.Rand
+	maxDepth int
+}
+
+// NewFilterFuzzer returns a new FilterFuzzer, seeded with the current time. It panics if the schema does not contain
+// any attributes that can be filtered on, attributes that are never returned are left out.
+func NewFilterFuzzer(s schema.ReferenceSchema) *FilterFuzzer {
+	f := &FilterFuzzer{
+		schema:   s,
+		maxDepth: 3,
+	}
+	if len(f.paths(f.schema.Attributes, true)) == 0 {
+		panic("schema does not contain any attributes to filter on")
+	}
+	return f.Seed(time.Now().UnixNano())
+}
+
+// MaxDepth sets the maximum nesting depth of the logical expressions ("and", "or", "not") and value paths, a depth of
+// 0 results in a single comparison or presence test. The default is 3.
+func (f *FilterFuzzer) MaxDepth(depth int) *FilterFuzzer {
+	if depth < 0 {
+		panic("depth must be >= 0")
+	}
+	f.maxDepth = depth
+	return f
+}
+
+// RandSource causes the FilterFuzzer to get values from the given source of randomness.
+func (f *FilterFuzzer) RandSource(s rand.Source) *FilterFuzzer {
+	f.r = rand.New(s)
+	return f
+}
+
+// Seed makes the FilterFuzzer get values from a new source of randomness, seeded with the given value.
+func (f *FilterFuzzer) Seed(seed int64) *FilterFuzzer {
+	return f.RandSource(rand.NewSource(seed))
+}
+
+// Fuzz creates a random filter expression. The operators fit the types of the attributes, e.g. "co" is never used
+// for boolean attributes, and value paths are used for multi valued complex attributes.
+func (f *FilterFuzzer) Fuzz() string {
+	expression, _ := f.expression(f.context(nil), f.depth(), true)
+	return expression
+}
+
+// FuzzMatching creates a random filter expression that matches the given resource, e.g. a resource created by
+// Fuzzer.Fuzz. Strings are compared case insensitive, so the case of the values of string attributes is sometimes
+// changed, except for attributes with the "caseExact" characteristic. These are always matched in the same case.
+func (f *FilterFuzzer) FuzzMatching(resource map[string]interface{}) string {
+	expression, _ := f.expression(f.context([]map[string]interface{}{resource}), f.depth(), true)
+	return expression
+}
+
+// filterContext contains the attributes that can be referenced in a (sub) expression. The resources are the complex
+// values the expression is evaluated against, nil if the expression does not need to match. An expression that has
+// to be true only needs to match one of the resources, an expression that has to be false may not match any of them.
+type filterContext struct {
+	attributes []*schema.Attribute
+	top        bool
+	resources  []map[string]interface{}
+}
+
+func (f *FilterFuzzer) context(resources []map[string]interface{}) filterContext {
+	return filterContext{attributes: f.schema.Attributes, top: true, resources: resources}
+}
+
+func (c filterContext) matching() bool {
+	return c.resources != nil
+}
+
+// depth returns a random depth up to the maximum depth.
+func (f *FilterFuzzer) depth() int {
+	return f.r.Intn(f.maxDepth + 1)
+}
+
+// expression returns a (sub) expression and its logical operator, if any. The expression is true (or false) for the
+// resources of the context, depending on the given value of want.
+func (f *FilterFuzzer) expression(c filterContext, depth int, want bool) (string, string) {
+	if depth == 0 {
+		return f.attributeExpression(c, 0, want), ""
+	}
+
+	random := func() bool { return !c.matching() || f.r.Intn(2) == 0 }
+	switch f.r.Intn(4) {
+	case 0:
+		// An expression that should be false for multiple resources can not be negated, this would require an
+		// expression that matches all of them.
+		if !c.matching() || want || len(c.resources) == 1 {
+			e, _ := f.expression(c, depth-1, !want)
+			return fmt.Sprintf("not (%s)", e), ""
+		}
+		fallthrough
+	case 1:
+		left, right := want, want
+		if c.matching() && !want {
+			if f.r.Intn(2) == 0 {
+				right = random()
+			} else {
+				left = random()
+			}
+		}
+		return fmt.Sprintf("%s and %s", f.operand(c, depth-1, left, "and"), f.operand(c, depth-1, right, "and")), "and"
+	case 2:
+		left, right := want, want
+		if c.matching() && want {
+			if f.r.Intn(2) == 0 {
+				right = random()
+			} else {
+				left = random()
+			}
+		}
+		return fmt.Sprintf("%s or %s", f.operand(c, depth-1, left, "or"), f.operand(c, depth-1, right, "or")), "or"
+	default:
+		return f.attributeExpression(c, depth, want), ""
+	}
+}
+
+// operand returns an operand of the logical operator op, grouped in parentheses if needed (an "or" in an "and") and
+// sometimes if not.
+func (f *FilterFuzzer) operand(c filterContext, depth int, want bool, op string) string {
+	e, eop := f.expression(c, depth, want)
+	if (op == "and" && eop == "or") || f.r.Intn(5) == 0 {
+		return fmt.Sprintf("(%s)", e)
+	}
+	return e
+}
+
+// filterPath is an attribute path within a context, e.g. "userName" or "name.givenName".
+type filterPath struct {
+	name      string
+	attribute *schema.Attribute
+}
+
+// paths returns the paths of the (sub) attributes that can be filtered on. Complex attributes can only be checked for
+// presence.
+func (f *FilterFuzzer) paths(attributes []*schema.Attribute, sub bool) []filterPath {
+	var paths []filterPath
+	for _, attribute := range attributes {
+		if attribute.Returned == schema.Never {
+			continue
+		}
+		paths = append(paths, filterPath{name: attribute.Name, attribute: attribute})
+		if attribute.Type != schema.ComplexType || !sub {
+			continue
+		}
+		for _, s := range attribute.SubAttributes {
+			if s.Returned != schema.Never && s.Type != schema.ComplexType {
+				paths = append(paths, filterPath{name: attribute.Name + "." + s.Name, attribute: s})
+			}
+		}
+	}
+	return paths
+}
+
+// valuePaths returns the multi valued complex attributes that can be filtered with a value path. A value path that has
+// to match requires the attribute to be present.
+func (f *FilterFuzzer) valuePaths(c filterContext, want bool) []*schema.Attribute {
+	if !c.top {
+		return nil
+	}
+	var attributes []*schema.Attribute
+	for _, attribute := range c.attributes {
+		if attribute.Returned == schema.Never || attribute.Type != schema.ComplexType || !attribute.MultiValued ||
+			len(f.paths(attribute.SubAttributes, false)) == 0 {
+			continue
+		}
+		if c.matching() && want && len(elements(c.resources[0][attribute.Name])) == 0 {
+			continue
+		}
+		attributes = append(attributes, attribute)
+	}
+	return attributes
+}
+
+// attributeExpression returns a comparison, presence test or value path, the latter only if the depth allows it.
+func (f *FilterFuzzer) attributeExpression(c filterContext, depth int, want bool) string {
+	if attributes := f.valuePaths(c, want); depth > 0 && len(attributes) != 0 && f.r.Intn(4) == 0 {
+		attribute := attributes[f.r.Intn(len(attributes))]
+		sub := filterContext{attributes: attribute.SubAttributes}
+		if c.matching() {
+			// A value path that has to be true matches one of the values, one that has to be false none of them. The
+			// filter of a value path of an attribute that is not present can be anything.
+			values := elements(c.resources[0][attribute.Name])
+			if want {
+				i := f.r.Intn(len(values))
+				sub.resources = values[i : i+1]
+			} else {
+				sub.resources = values
+			}
+		}
+		e, _ := f.expression(sub, depth-1, want)
+		return fmt.Sprintf("%s[%s]", f.prefix(c, attribute.Name), e)
+	}
+
+	paths := f.paths(c.attributes, c.top)
+	f.r.Shuffle(len(paths), func(i, j int) { paths[i], paths[j] = paths[j], paths[i] })
+	if !c.matching() {
+		return f.comparison(c, paths[0])
+	}
+	for _, p := range paths {
+		if e, ok := f.matchingComparison(c, p, want); ok {
+			return e
+		}
+	}
+	// None of the attributes can be compared, e.g. all of them are present when the expression should be false.
+	e, _ := f.matchingComparison(c, paths[0], !want)
+	return fmt.Sprintf("not (%s)", e)
+}
+
+// prefix sometimes prefixes the given path with the URN of the schema.
+func (f *FilterFuzzer) prefix(c filterContext, path string) string {
+	if c.top && f.schema.ID != "" && f.r.Intn(10) == 0 {
+		return f.schema.ID + ":" + path
+	}
+	return path
+}
+
+// comparison returns a random comparison or presence test of the given attribute.
+func (f *FilterFuzzer) comparison(c filterContext, p filterPath) string {
+	ops := operators[p.attribute.Type]
+	if len(ops) == 0 || f.r.Intn(6) == 0 {
+		return fmt.Sprintf("%s pr", f.prefix(c, p.name))
+	}
+	op := ops[f.r.Intn(len(ops))]
+	return fmt.Sprintf("%s %s %s", f.prefix(c, p.name), op, formatValue(f.randValue(p.attribute)))
+}
+
+// matchingComparison returns a comparison of the given attribute that is true for one of the resources of the
+// context, or false for all of them. False is returned if this is not possible.
+func (f *FilterFuzzer) matchingComparison(c filterContext, p filterPath, want bool) (string, bool) {
+	values := pathValues(c.resources, p.name)
+	name, a := f.prefix(c, p.name), p.attribute
+	if len(values) == 0 {
+		if want {
+			return "", false
+		}
+		// Comparisons with attributes that are not present are false, except for "ne".
+		if ops := operators[a.Type]; len(ops) != 0 && f.r.Intn(2) == 0 {
+			if op := ops[f.r.Intn(len(ops))]; op != "ne" {
+				return fmt.Sprintf("%s %s %s", name, op, formatValue(f.randValue(a))), true
+			}
+		}
+		return fmt.Sprintf("%s pr", name), true
+	}
+	if a.Type == schema.ComplexType {
+		return fmt.Sprintf("%s pr", name), want
+	}
+
+	if want {
+		v := values[f.r.Intn(len(values))]
+		switch s, _ := v.(string); {
+		case f.r.Intn(6) == 0:
+			return fmt.Sprintf("%s pr", name), true
+		case (a.Type == schema.StringType || a.Type == schema.ReferenceType) && len(s) != 0 && f.r.Intn(2) == 0:
+			runes := []rune(f.changeCase(a, s))
+			i := f.r.Intn(len(runes))
+			j := i + 1 + f.r.Intn(len(runes)-i)
+			switch f.r.Intn(3) {
+			case 0:
+				return fmt.Sprintf("%s co %s", name, formatValue(string(runes[i:j]))), true
+			case 1:
+				return fmt.Sprintf("%s sw %s", name, formatValue(string(runes[:j]))), true
+			default:
+				return fmt.Sprintf("%s ew %s", name, formatValue(string(runes[i:]))), true
+			}
+		case a.Type != schema.BooleanType && a.Type != schema.BinaryType && a.Type != schema.ReferenceType && f.r.Intn(2) == 0:
+			return fmt.Sprintf("%s %s %s", name, []string{"ge", "le"}[f.r.Intn(2)], formatValue(v)), true
+		case len(s) != 0:
+			return fmt.Sprintf("%s eq %s", name, formatValue(f.changeCase(a, s))), true
+		default:
+			return fmt.Sprintf("%s eq %s", name, formatValue(v)), true
+		}
+	}
+
+	switch a.Type {
+	case schema.BooleanType:
+		b, _ := values[0].(bool)
+		for _, v := range values {
+			if v != b {
+				return "", false
+			}
+		}
+		return fmt.Sprintf("%s eq %t", name, !b), true
+	case schema.IntegerType, schema.DecimalType, schema.DateTimeType:
+		if f.r.Intn(2) == 0 {
+			min, max := values[0], values[0]
+			for _, v := range values {
+				if compareValues(v, min) < 0 {
+					min = v
+				}
+				if compareValues(v, max) > 0 {
+					max = v
+				}
+			}
+			if f.r.Intn(2) == 0 {
+				return fmt.Sprintf("%s gt %s", name, formatValue(max)), true
+			}
+			return fmt.Sprintf("%s lt %s", name, formatValue(min)), true
+		}
+	}
+	for {
+		v := f.randValue(a)
+		if a.Type == schema.StringType && len(a.CanonicalValues) != 0 {
+			v = randAlphaString(f.r, 12) // canonical values are likely to be present
+		}
+		if containsValue(values, v, false) {
+			continue
+		}
+		if s, ok := v.(string); ok && (a.Type == schema.StringType || a.Type == schema.ReferenceType) &&
+			!containsValue(values, s, true) && f.r.Intn(2) == 0 {
+			return fmt.Sprintf("%s co %s", name, formatValue(s)), true
+		}
+		return fmt.Sprintf("%s eq %s", name, formatValue(v)), true
+	}
+}
+
+// changeCase sometimes changes the case of the ASCII letters of the given value of a string attribute, unless the
+// attribute is case exact.
+func (f *FilterFuzzer) changeCase(a *schema.Attribute, s string) string {
+	if a.CaseExact || a.Type != schema.StringType || f.r.Intn(3) != 0 {
+		return s
+	}
+	b := []byte(s)
+	for i, c := range b {
+		if ('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') && f.r.Intn(2) == 0 {
+			b[i] = c ^ 0x20
+		}
+	}
+	return string(b)
+}
+
+// randValue returns a random value of the type of the given attribute.
+func (f *FilterFuzzer) randValue(a *schema.Attribute) interface{} {
+	switch a.Type {
+	case schema.BooleanType:
+		return f.r.Intn(2) == 0
+	case schema.IntegerType:
+		return f.r.Intn(2000) - 1000
+	case schema.DecimalType:
+		return math.Round((f.r.Float64()*2000-1000)*100) / 100
+	case schema.DateTimeType:
+		return randDateTime(f.r)
+	case schema.BinaryType:
+		return base64.StdEncoding.EncodeToString([]byte(randAlphaString(f.r, 10)))
+	case schema.ReferenceType:
+		return "https://example.com/" + randAlphaString(f.r, 10)
+	default:
+		if len(a.CanonicalValues) != 0 && f.r.Intn(2) == 0 {
+			return randStringFromSlice(f.r, a.CanonicalValues)
+		}
+		return randAlphaString(f.r, 1+f.r.Intn(10))
+	}
+}
+
+// formatValue formats the given value as a value of a filter, strings are JSON strings.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// pathValues returns the values of the attribute with the given path in the given resources, the values of multi
+// valued attributes are flattened.
+func pathValues(resources []map[string]interface{}, path string) []interface{} {
+	parts := strings.SplitN(path, ".", 2)
+	var values []interface{}
+	for _, resource := range resources {
+		value, ok := resource[parts[0]]
+		if !ok || value == nil {
+			continue
+		}
+		if len(parts) == 2 {
+			values = append(values, pathValues(elements(value), parts[1])...)
+			continue
+		}
+		if list, ok := value.([]interface{}); ok {
+			values = append(values, list...)
+		} else {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// containsValue checks whether the given value is one of the values, or a substring of one of them. Strings are
+// compared case insensitive.
+func containsValue(values []interface{}, v interface{}, substring bool) bool {
+	for _, value := range values {
+		s, ok1 := value.(string)
+		t, ok2 := v.(string)
+		switch {
+		case ok1 && ok2 && substring:
+			if strings.Contains(strings.ToLower(s), strings.ToLower(t)) {
+				return true
+			}
+		case ok1 && ok2:
+			if strings.EqualFold(s, t) {
+				return true
+			}
+		case compareValues(value, v) == 0:
+			return true
+		}
+	}
+	return false
+}
+
+// compareValues compares numbers (and dateTime strings) and returns -1, 0 or 1. Other values are equal if they are
+// the same.
+func compareValues(a, b interface{}) int {
+	x, ok1 := toFloat(a)
+	y, ok2 := toFloat(b)
+	if ok1 && ok2 {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	}
+	if s, ok := a.(string); ok {
+		if t, ok := b.(string); ok {
+			return strings.Compare(s, t)
+		}
+	}
+	if a == b {
+		return 0
+	}
+	return 1
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
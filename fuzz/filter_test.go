@@ -0,0 +1,442 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/scim2/tools/schema"
+)
+
+func filterSchema() schema.ReferenceSchema {
+	return schema.ReferenceSchema{
+		ID: "urn:ietf:params:scim:schemas:core:2.0:User",
+		Attributes: []*schema.Attribute{
+			{Name: "userName", Type: schema.StringType, Required: true, CaseExact: true},
+			{Name: "password", Type: schema.StringType, Returned: schema.Never},
+			{Name: "userType", Type: schema.StringType, CanonicalValues: []string{"Employee", "Contractor"}},
+			{Name: "active", Type: schema.BooleanType},
+			{Name: "age", Type: schema.IntegerType},
+			{Name: "score", Type: schema.DecimalType},
+			{Name: "birthday", Type: schema.DateTimeType},
+			{Name: "photo", Type: schema.BinaryType},
+			{Name: "profileUrl", Type: schema.ReferenceType},
+			{Name: "nickNames", Type: schema.StringType, MultiValued: true},
+			{Name: "name", Type: schema.ComplexType, SubAttributes: []*schema.Attribute{
+				{Name: "givenName", Type: schema.StringType},
+				{Name: "familyName", Type: schema.StringType},
+			}},
+			{Name: "emails", Type: schema.ComplexType, MultiValued: true, SubAttributes: []*schema.Attribute{
+				{Name: "value", Type: schema.StringType},
+				{Name: "type", Type: schema.StringType, CanonicalValues: []string{"work", "home"}},
+				{Name: "primary", Type: schema.BooleanType},
+			}},
+		},
+	}
+}
+
+// filterNode is a parsed filter expression.
+type filterNode struct {
+	op          string // and, or, not, [ (value path), pr or a comparison operator
+	path        string
+	value       interface{}
+	left, right *filterNode
+}
+
+// depth returns the nesting depth of the expression, chains of the same logical operator count as one level.
+func (n *filterNode) depth() int {
+	switch n.op {
+	case "and", "or":
+		var depth int
+		for _, operand := range []*filterNode{n.left, n.right} {
+			d := operand.depth()
+			if operand.op == n.op {
+				d--
+			}
+			if d > depth {
+				depth = d
+			}
+		}
+		return 1 + depth
+	case "not", "[":
+		return 1 + n.left.depth()
+	default:
+		return 0
+	}
+}
+
+// leafs calls fn for every comparison and presence test, with the path of the attribute relative to the schema.
+func (n *filterNode) leafs(prefix string, fn func(path, op string)) {
+	switch n.op {
+	case "and", "or":
+		n.left.leafs(prefix, fn)
+		n.right.leafs(prefix, fn)
+	case "not":
+		n.left.leafs(prefix, fn)
+	case "[":
+		n.left.leafs(prefix+n.path+".", fn)
+	default:
+		fn(prefix+n.path, n.op)
+	}
+}
+
+// eval evaluates the filter against the given resource with the given attributes, strings are compared case
+// insensitive unless the attribute is case exact.
+func (n *filterNode) eval(resource map[string]interface{}, attributes []*schema.Attribute) bool {
+	switch n.op {
+	case "and":
+		return n.left.eval(resource, attributes) && n.right.eval(resource, attributes)
+	case "or":
+		return n.left.eval(resource, attributes) || n.right.eval(resource, attributes)
+	case "not":
+		return !n.left.eval(resource, attributes)
+	case "[":
+		var subAttributes []*schema.Attribute
+		if a := attributeByPath(attributes, n.path); a != nil {
+			subAttributes = a.SubAttributes
+		}
+		for _, element := range elements(resource[n.path]) {
+			if n.left.eval(element, subAttributes) {
+				return true
+			}
+		}
+		return false
+	case "pr":
+		return len(pathValues([]map[string]interface{}{resource}, n.path)) != 0
+	}
+	a := attributeByPath(attributes, n.path)
+	for _, v := range pathValues([]map[string]interface{}{resource}, n.path) {
+		if compare(v, n.op, n.value, a != nil && a.CaseExact) {
+			return true
+		}
+	}
+	return false
+}
+
+func compare(v interface{}, op string, value interface{}, caseExact bool) bool {
+	var c int
+	switch value := value.(type) {
+	case string:
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		t := value
+		if !caseExact {
+			s, t = strings.ToLower(s), strings.ToLower(t)
+		}
+		switch op {
+		case "co":
+			return strings.Contains(s, t)
+		case "sw":
+			return strings.HasPrefix(s, t)
+		case "ew":
+			return strings.HasSuffix(s, t)
+		}
+		c = strings.Compare(s, t)
+	case bool:
+		b, ok := v.(bool)
+		if !ok {
+			return false
+		}
+		if b != value {
+			c = 1
+		}
+	case float64:
+		x, ok := toFloat(v)
+		if !ok {
+			return false
+		}
+		c = compareValues(x, value)
+	default:
+		return false
+	}
+	switch op {
+	case "eq":
+		return c == 0
+	case "ne":
+		return c != 0
+	case "gt":
+		return c > 0
+	case "ge":
+		return c >= 0
+	case "lt":
+		return c < 0
+	case "le":
+		return c <= 0
+	}
+	return false
+}
+
+// filterParser parses the filters of the FilterFuzzer.
+type filterParser struct {
+	tokens []string
+	urn    string
+}
+
+func parseFilter(filter, urn string) (*filterNode, error) {
+	p := &filterParser{urn: urn}
+	for i := 0; i < len(filter); {
+		switch ch := filter[i]; {
+		case ch == ' ':
+			i++
+		case strings.IndexByte("()[]", ch) != -1:
+			p.tokens = append(p.tokens, string(ch))
+			i++
+		case ch == '"':
+			j := i + 1
+			for ; j < len(filter) && filter[j] != '"'; j++ {
+				if filter[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(filter) {
+				return nil, fmt.Errorf("unterminated string: %s", filter[i:])
+			}
+			p.tokens = append(p.tokens, filter[i:j+1])
+			i = j + 1
+		default:
+			j := i
+			for ; j < len(filter) && strings.IndexByte(` ()[]"`, filter[j]) == -1; j++ {
+			}
+			p.tokens = append(p.tokens, filter[i:j])
+			i = j
+		}
+	}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if len(p.tokens) != 0 {
+		return nil, fmt.Errorf("unexpected tokens: %v", p.tokens)
+	}
+	return n, nil
+}
+
+func (p *filterParser) next() string {
+	if len(p.tokens) == 0 {
+		return ""
+	}
+	t := p.tokens[0]
+	p.tokens = p.tokens[1:]
+	return t
+}
+
+func (p *filterParser) peek() string {
+	if len(p.tokens) == 0 {
+		return ""
+	}
+	return p.tokens[0]
+}
+
+func (p *filterParser) expect(t string) error {
+	if next := p.next(); next != t {
+		return fmt.Errorf("expected %q, got %q", t, next)
+	}
+	return nil
+}
+
+func (p *filterParser) or() (*filterNode, error) {
+	left, err := p.and()
+	for err == nil && p.peek() == "or" {
+		p.next()
+		var right *filterNode
+		if right, err = p.and(); err == nil {
+			left = &filterNode{op: "or", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *filterParser) and() (*filterNode, error) {
+	left, err := p.factor()
+	for err == nil && p.peek() == "and" {
+		p.next()
+		var right *filterNode
+		if right, err = p.factor(); err == nil {
+			left = &filterNode{op: "and", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *filterParser) factor() (*filterNode, error) {
+	switch t := p.next(); t {
+	case "not":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return &filterNode{op: "not", left: n}, p.expect(")")
+	case "(":
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return n, p.expect(")")
+	case "", ")", "[", "]":
+		return nil, fmt.Errorf("unexpected token: %q", t)
+	default:
+		path := t
+		if p.urn != "" && strings.HasPrefix(strings.ToLower(path), strings.ToLower(p.urn)+":") {
+			path = path[len(p.urn)+1:]
+		}
+		if p.peek() == "[" {
+			p.next()
+			n, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			return &filterNode{op: "[", path: path, left: n}, p.expect("]")
+		}
+		op := p.next()
+		if op == "pr" {
+			return &filterNode{op: op, path: path}, nil
+		}
+		if _, ok := map[string]bool{
+			"eq": true, "ne": true, "co": true, "sw": true, "ew": true, "gt": true, "ge": true, "lt": true, "le": true,
+		}[op]; !ok {
+			return nil, fmt.Errorf("invalid operator: %q", op)
+		}
+		var value interface{}
+		raw := p.next()
+		switch {
+		case strings.HasPrefix(raw, `"`):
+			var s string
+			if err := json.Unmarshal([]byte(raw), &s); err != nil {
+				return nil, err
+			}
+			value = s
+		case raw == "true" || raw == "false":
+			value = raw == "true"
+		default:
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value: %q", raw)
+			}
+			value = f
+		}
+		return &filterNode{op: op, path: path, value: value}, nil
+	}
+}
+
+func TestFilterFuzzer_Fuzz(t *testing.T) {
+	s := filterSchema()
+	for _, depth := range []int{0, 1, 3} {
+		f := NewFilterFuzzer(s).MaxDepth(depth)
+		for i := 0; i < 500; i++ {
+			filter := f.Fuzz()
+			n, err := parseFilter(filter, s.ID)
+			if err != nil {
+				t.Fatalf("%s: %v", filter, err)
+			}
+			if n.depth() > depth {
+				t.Errorf("%s: depth %d exceeds %d", filter, n.depth(), depth)
+			}
+			n.leafs("", func(path, op string) {
+				a := attributeByPath(s.Attributes, path)
+				if a == nil {
+					t.Errorf("%s: unknown attribute %s", filter, path)
+					return
+				}
+				if a.Returned == schema.Never {
+					t.Errorf("%s: %s is never returned", filter, path)
+				}
+				if op == "pr" {
+					return
+				}
+				var ok bool
+				for _, o := range operators[a.Type] {
+					ok = ok || o == op
+				}
+				if !ok {
+					t.Errorf("%s: operator %s does not apply to %s attribute %s", filter, op, a.Type, path)
+				}
+			})
+		}
+	}
+}
+
+func TestFilterFuzzer_FuzzMatching(t *testing.T) {
+	s := filterSchema()
+	f := NewFilterFuzzer(s).MaxDepth(4)
+	for i := int64(0); i < 500; i++ {
+		fuzzer := New(s).Seed(i).NumElements(0, 3)
+		if i%2 == 0 {
+			fuzzer.Realistic()
+		}
+		resource := fuzzer.Fuzz()
+		filter := f.FuzzMatching(resource)
+		n, err := parseFilter(filter, s.ID)
+		if err != nil {
+			t.Fatalf("%s: %v", filter, err)
+		}
+		if !n.eval(resource, s.Attributes) {
+			t.Errorf("%s does not match %v", filter, resource)
+		}
+	}
+}
+
+func TestFilterFuzzer_FuzzMatching_caseExact(t *testing.T) {
+	s := schema.ReferenceSchema{
+		Attributes: []*schema.Attribute{
+			{Name: "userName", Type: schema.StringType, CaseExact: true},
+			{Name: "displayName", Type: schema.StringType},
+		},
+	}
+	resource := map[string]interface{}{"userName": "DiWu", "displayName": "Quint Daenen"}
+	f := NewFilterFuzzer(s).Seed(1).MaxDepth(0)
+	var changed bool
+	for i := 0; i < 500; i++ {
+		filter := f.FuzzMatching(resource)
+		n, err := parseFilter(filter, s.ID)
+		if err != nil {
+			t.Fatalf("%s: %v", filter, err)
+		}
+		if !n.eval(resource, s.Attributes) {
+			t.Errorf("%s does not match %v", filter, resource)
+		}
+		if value, ok := n.value.(string); ok && (n.op == "eq" || n.op == "co" || n.op == "sw" || n.op == "ew") {
+			if contains := strings.Contains(resource[n.path].(string), value); n.path == "userName" && !contains {
+				t.Errorf("%s: case of the case exact userName changed", filter)
+			} else if n.path == "displayName" && !contains {
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		t.Error("case of displayName never changed")
+	}
+}
+
+func TestFilterFuzzer_Seed(t *testing.T) {
+	a, b := NewFilterFuzzer(filterSchema()).Seed(1), NewFilterFuzzer(filterSchema()).Seed(1)
+	for i := 0; i < 10; i++ {
+		if fa, fb := a.Fuzz(), b.Fuzz(); fa != fb {
+			t.Errorf("filters with the same seed differ: %s, %s", fa, fb)
+		}
+	}
+}
+
+func ExampleFilterFuzzer_FuzzMatching() {
+	s := schema.ReferenceSchema{
+		Attributes: []*schema.Attribute{
+			{Name: "userName", Type: schema.StringType, Required: true},
+			{Name: "emails", Type: schema.ComplexType, MultiValued: true, SubAttributes: []*schema.Attribute{
+				{Name: "value", Type: schema.StringType, Required: true},
+				{Name: "primary", Type: schema.BooleanType, Required: true},
+			}},
+		},
+	}
+	resource := New(s).Seed(1).NumElements(2, 2).Realistic().Fuzz()
+	fmt.Println(resource)
+	fmt.Println(NewFilterFuzzer(s).Seed(43).FuzzMatching(resource))
+	// Output:
+	// map[emails:[map[primary:true value:charlotte.miller@example.com] map[primary:false value:emma.jackson@example.com]] userName:abrown47]
+	// not (userName co "vMMhE") and emails[value eq "QuO" or value ew "XaMple.CoM"]
+}
@@ -20,19 +20,24 @@ type Fuzzer struct {
 	emptyChance float64
 	minElements int
 	maxElements int
+
+	generators map[string]func(r *rand.Rand) interface{}
+	realistic  bool
+	// address is the realistic address of the complex value that is being filled.
+	address *address
 }
 
-// New returns a new Fuzzer.
+// New returns a new Fuzzer, seeded with the current time. Use Seed to make the resources reproducible.
 func New(schema schema.ReferenceSchema) *Fuzzer {
-	r := rand.New(rand.NewSource(time.Now().Unix()))
-	return &Fuzzer{
+	f := &Fuzzer{
 		schema:      schema,
-		fuzzer:      fuzz.New().RandSource(r),
-		r:           r,
+		fuzzer:      fuzz.New(),
 		emptyChance: .2,
 		minElements: 1,
 		maxElements: 10,
+		generators:  make(map[string]func(r *rand.Rand) interface{}),
 	}
+	return f.Seed(time.Now().UnixNano())
 }
 
 // EmptyChance sets the probability of creating an empty field map to the given chance.
@@ -82,6 +87,33 @@ func neverEmpty(name string, attribute *schema.Attribute) {
 	}
 }
 
+// Generator makes the Fuzzer use the given function to generate the values of the attribute with the given path, e.g.
+// "locale" or "emails.value". The function gets called for every value of a multi valued attribute, the values of
+// complex attributes are (a map of) their sub attributes. Whether the attribute gets a value still depends on the other
+// settings of the Fuzzer, a nil value leaves the attribute out.
+func (f *Fuzzer) Generator(path string, generate func(r *rand.Rand) interface{}) *Fuzzer {
+	if attributeByPath(f.schema.Attributes, path) == nil {
+		panic(fmt.Sprintf("unknown attribute: %s", path))
+	}
+	f.generators[strings.ToLower(path)] = generate
+	return f
+}
+
+// attributeByPath returns the attribute with the given path, or nil if it does not exist.
+func attributeByPath(attributes []*schema.Attribute, path string) *schema.Attribute {
+	n := strings.SplitN(path, ".", 2)
+	for _, attribute := range attributes {
+		if !strings.EqualFold(n[0], attribute.Name) {
+			continue
+		}
+		if len(n) == 1 {
+			return attribute
+		}
+		return attributeByPath(attribute.SubAttributes, n[1])
+	}
+	return nil
+}
+
 func shouldFill(attribute *schema.Attribute) bool {
 	return attribute.Required || attribute.Type == schema.ComplexType
 }
@@ -100,13 +132,21 @@ func (f *Fuzzer) NumElements(atLeast, atMost int) *Fuzzer {
 	return f
 }
 
-// RandSource causes the Fuzzer to get values from the given source of randomness.
+// RandSource causes the Fuzzer to get values from the given source of randomness. All random choices are drawn from
+// this source, so a Fuzzer with the same settings and a source in the same state creates the same resources.
 func (f *Fuzzer) RandSource(s rand.Source) *Fuzzer {
-	f.r = rand.New(s)
-	f.fuzzer.RandSource(s)
+	r := rand.New(s)
+	f.r = r
+	f.fuzzer.RandSource(r)
 	return f
 }
 
+// Seed makes the Fuzzer get values from a new source of randomness, seeded with the given value. The resources that
+// are created after seeding can be reproduced by using the same seed (and settings) again.
+func (f *Fuzzer) Seed(seed int64) *Fuzzer {
+	return f.RandSource(rand.NewSource(seed))
+}
+
 func (f *Fuzzer) elementCount() int {
 	if f.minElements == f.maxElements {
 		return f.minElements
@@ -114,13 +154,15 @@ func (f *Fuzzer) elementCount() int {
 	return f.minElements + f.r.Intn(f.maxElements-f.minElements+1)
 }
 
-func (f *Fuzzer) fuzzAttribute(resource map[string]interface{}, attribute *schema.Attribute, c fuzz.Continue) {
+// fuzzAttribute fills the given attribute of the resource, the path is the path of the parent of the attribute.
+func (f *Fuzzer) fuzzAttribute(resource map[string]interface{}, path string, attribute *schema.Attribute, c fuzz.Continue) {
+	path += strings.ToLower(attribute.Name)
 	if attribute.MultiValued {
 		var elements []interface{}
-		for i := 0; i < f.elementCount(); i++ {
-			value := f.fuzzSingleAttribute(attribute, c)
+		for i, n := 0, f.elementCount(); i < n; i++ {
+			value := f.fuzzSingleAttribute(path, attribute, c)
 			if value != nil {
-				elements = append(elements, f.fuzzSingleAttribute(attribute, c))
+				elements = append(elements, value)
 			}
 		}
 		if len(elements) != 0 {
@@ -130,14 +172,24 @@ func (f *Fuzzer) fuzzAttribute(resource map[string]interface{}, attribute *schem
 	}
 
 	if shouldFill(attribute) || f.shouldFill() {
-		value := f.fuzzSingleAttribute(attribute, c)
+		value := f.fuzzSingleAttribute(path, attribute, c)
 		if value != nil {
-			resource[attribute.Name] = f.fuzzSingleAttribute(attribute, c)
+			resource[attribute.Name] = value
 		}
 	}
 }
 
-func (f *Fuzzer) fuzzSingleAttribute(attribute *schema.Attribute, c fuzz.Continue) interface{} {
+// fuzzSingleAttribute returns a value of the attribute with the given (lower case) path.
+func (f *Fuzzer) fuzzSingleAttribute(path string, attribute *schema.Attribute, c fuzz.Continue) interface{} {
+	if generate, ok := f.generators[path]; ok {
+		return generate(c.Rand)
+	}
+	if f.realistic {
+		if value, ok := f.realisticValue(path, attribute, c.Rand); ok {
+			return value
+		}
+	}
+
 	switch attribute.Type {
 	case schema.StringType, schema.ReferenceType:
 		var randString string
@@ -162,12 +214,13 @@ func (f *Fuzzer) fuzzSingleAttribute(attribute *schema.Attribute, c fuzz.Continu
 		c.Fuzz(&randInt)
 		return randInt
 	case schema.DateTimeType:
-		randDateTimeString := randDateTime()
+		randDateTimeString := randDateTime(c.Rand)
 		return randDateTimeString
 	case schema.ComplexType:
 		complexResource := make(map[string]interface{})
+		f.address = nil
 		for _, subAttribute := range attribute.SubAttributes {
-			f.fuzzAttribute(complexResource, subAttribute, c)
+			f.fuzzAttribute(complexResource, path+".", subAttribute, c)
 		}
 		if len(complexResource) == 0 {
 			return nil
@@ -182,7 +235,7 @@ func (f *Fuzzer) newResourceFuzzer() func(resource *map[string]interface{}, c fu
 	return func(r *map[string]interface{}, c fuzz.Continue) {
 		resource := make(map[string]interface{})
 		for _, attribute := range f.schema.Attributes {
-			f.fuzzAttribute(resource, attribute, c)
+			f.fuzzAttribute(resource, "", attribute, c)
 		}
 		*r = resource
 	}
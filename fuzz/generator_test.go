@@ -0,0 +1,84 @@
+package fuzz
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/scim2/tools/schema"
+)
+
+func TestFuzzer_Generator(t *testing.T) {
+	s := schema.ReferenceSchema{
+		Attributes: []*schema.Attribute{
+			{Name: "locale", Type: schema.StringType, Required: true},
+			{Name: "nickName", Type: schema.StringType},
+			{
+				Name:        "emails",
+				Type:        schema.ComplexType,
+				MultiValued: true,
+				SubAttributes: []*schema.Attribute{
+					{Name: "value", Type: schema.StringType, Required: true},
+				},
+			},
+		},
+	}
+
+	f := New(s).
+		NumElements(2, 2).
+		Generator("locale", func(r *rand.Rand) interface{} {
+			return []string{"en-US", "nl-BE"}[r.Intn(2)]
+		}).
+		Generator("Emails.Value", func(r *rand.Rand) interface{} {
+			return fmt.Sprintf("user%d@example.com", r.Intn(100))
+		}).
+		Generator("nickName", func(r *rand.Rand) interface{} {
+			return nil
+		})
+	for i := 0; i < 100; i++ {
+		resource := f.Fuzz()
+		if locale := resource["locale"]; locale != "en-US" && locale != "nl-BE" {
+			t.Errorf("unexpected locale: %v", locale)
+		}
+		if _, ok := resource["nickName"]; ok {
+			t.Error("nickName should be left out")
+		}
+		emails, ok := resource["emails"].([]interface{})
+		if !ok || len(emails) != 2 {
+			t.Fatalf("unexpected emails: %v", resource["emails"])
+		}
+		for _, email := range emails {
+			if value := email.(map[string]interface{})["value"].(string); !strings.HasSuffix(value, "@example.com") {
+				t.Errorf("unexpected email: %s", value)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("panic expected for unknown attribute")
+		}
+	}()
+	New(s).Generator("emails.display", func(r *rand.Rand) interface{} { return nil })
+}
+
+func ExampleFuzzer_Generator() {
+	s := schema.ReferenceSchema{
+		Attributes: []*schema.Attribute{
+			{Name: "phoneNumbers", Type: schema.ComplexType, MultiValued: true, SubAttributes: []*schema.Attribute{
+				{Name: "value", Type: schema.StringType, Required: true},
+			}},
+		},
+	}
+	resource := New(s).
+		Seed(1).
+		NumElements(1, 1).
+		Generator("phoneNumbers.value", func(r *rand.Rand) interface{} {
+			return fmt.Sprintf("+3247%07d", r.Intn(10000000))
+		}).
+		Fuzz()
+	fmt.Println(resource)
+	// Output:
+	// map[phoneNumbers:[map[value:+32478498081]]]
+}
@@ -0,0 +1,298 @@
+package fuzz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/gofuzz"
+	"github.com/scim2/tools/schema"
+)
+
+// Violation is a rule of the schema that an invalid resource breaks.
+type Violation string
+
+const (
+	// WrongType sets an attribute to a value of another type, e.g. a number for a string attribute.
+	WrongType Violation = "wrongType"
+	// MissingRequired leaves out a required attribute.
+	MissingRequired Violation = "missingRequired"
+	// UnknownCanonicalValue sets an attribute with canonical values to a value that is not one of them.
+	UnknownCanonicalValue Violation = "unknownCanonicalValue"
+	// MultiplePrimary marks multiple values of a multi valued attribute as primary.
+	MultiplePrimary Violation = "multiplePrimary"
+	// ReadOnlySet sets an attribute that is read-only.
+	ReadOnlySet Violation = "readOnlySet"
+	// DuplicateKey adds an attribute twice, with names that only differ in case.
+	DuplicateKey Violation = "duplicateKey"
+	// OverNesting makes a sub attribute of a complex attribute complex itself.
+	OverNesting Violation = "overNesting"
+)
+
+// Violations are all the rules that an invalid resource can break.
+var Violations = []Violation{
+	WrongType, MissingRequired, UnknownCanonicalValue, MultiplePrimary, ReadOnlySet, DuplicateKey, OverNesting,
+}
+
+// SCIMType returns the "scimType" of the error that a service provider is expected to respond with (RFC 7644), e.g.
+// "invalidValue" for a missing required attribute. Note that a service provider can also choose to ignore read-only
+// attributes and to accept values that are not canonical.
+func (v Violation) SCIMType() string {
+	switch v {
+	case ReadOnlySet:
+		return "mutability"
+	case DuplicateKey, OverNesting:
+		return "invalidSyntax"
+	default:
+		return "invalidValue"
+	}
+}
+
+// InvalidResource is a resource that breaks exactly one rule of the schema.
+type InvalidResource struct {
+	Resource  map[string]interface{}
+	Violation Violation
+	// Path is the path of the attribute that breaks the rule, e.g. "userName" or "emails.primary".
+	Path string
+}
+
+// FuzzInvalid creates a resource that breaks one of the given rules (or any rule if none are given), chosen at random
+// from the rules that apply to the schema. Apart from the violation the resource is valid: required attributes are
+// present, read-only attributes are left out and at most one value of a multi valued attribute is primary. An error is returned if none of the rules
+// apply to the schema, e.g. MultiplePrimary without multi valued attributes that have a "primary" sub attribute.
+func (f *Fuzzer) FuzzInvalid(violations ...Violation) (InvalidResource, error) {
+	if len(violations) == 0 {
+		violations = Violations
+	}
+
+	var applicable []Violation
+	for _, v := range violations {
+		if len(f.targets(v)) != 0 {
+			applicable = append(applicable, v)
+		}
+	}
+	if len(applicable) == 0 {
+		return InvalidResource{}, fmt.Errorf("none of the violations apply to the schema: %v", violations)
+	}
+
+	var invalid InvalidResource
+	f.fuzzer.Funcs(func(invalid *InvalidResource, c fuzz.Continue) {
+		resource := make(map[string]interface{})
+		for _, attribute := range f.schema.Attributes {
+			f.fuzzAttribute(resource, "", attribute, c)
+		}
+		f.sanitize(resource)
+		f.complete(resource, c)
+
+		v := applicable[c.Rand.Intn(len(applicable))]
+		targets := f.targets(v)
+		t := targets[c.Rand.Intn(len(targets))]
+		f.violate(resource, v, t, c)
+		*invalid = InvalidResource{Resource: resource, Violation: v, Path: t.path()}
+	}).Fuzz(&invalid)
+	return invalid, nil
+}
+
+// target is an attribute of the schema, the parent is nil for attributes that are not sub attributes.
+type target struct {
+	parent, attribute *schema.Attribute
+}
+
+func (t target) path() string {
+	if t.parent == nil {
+		return t.attribute.Name
+	}
+	return t.parent.Name + "." + t.attribute.Name
+}
+
+func (t target) readOnly() bool {
+	return t.attribute.Mutability == schema.ReadOnly || (t.parent != nil && t.parent.Mutability == schema.ReadOnly)
+}
+
+// targets returns the attributes that can break the given rule.
+func (f *Fuzzer) targets(v Violation) []target {
+	var targets []target
+	for _, attribute := range f.schema.Attributes {
+		all := []target{{attribute: attribute}}
+		for _, sub := range attribute.SubAttributes {
+			all = append(all, target{parent: attribute, attribute: sub})
+		}
+		for _, t := range all {
+			if t.readOnly() != (v == ReadOnlySet) {
+				continue
+			}
+			a := t.attribute
+			var ok bool
+			switch v {
+			case WrongType, ReadOnlySet:
+				ok = true
+			case MissingRequired:
+				ok = a.Required
+			case UnknownCanonicalValue:
+				ok = len(a.CanonicalValues) != 0 && a.Type == schema.StringType
+			case MultiplePrimary:
+				ok = t.parent != nil && t.parent.MultiValued && strings.EqualFold(a.Name, "primary") &&
+					a.Type == schema.BooleanType
+			case DuplicateKey:
+				ok = t.parent == nil && strings.ToUpper(a.Name) != strings.ToLower(a.Name)
+			case OverNesting:
+				ok = t.parent != nil && a.Type != schema.ComplexType
+			}
+			if ok {
+				targets = append(targets, t)
+			}
+		}
+	}
+	return targets
+}
+
+// sanitize removes the read-only attributes from the given resource and makes sure that at most one value of every
+// multi valued attribute is primary.
+func (f *Fuzzer) sanitize(resource map[string]interface{}) {
+	for _, attribute := range f.schema.Attributes {
+		if attribute.Mutability == schema.ReadOnly {
+			delete(resource, attribute.Name)
+			continue
+		}
+		var primary bool
+		for _, element := range elements(resource[attribute.Name]) {
+			for _, sub := range attribute.SubAttributes {
+				if sub.Mutability == schema.ReadOnly {
+					delete(element, sub.Name)
+					continue
+				}
+				if attribute.MultiValued && strings.EqualFold(sub.Name, "primary") && element[sub.Name] == true {
+					if primary {
+						element[sub.Name] = false
+					}
+					primary = true
+				}
+			}
+		}
+	}
+}
+
+// complete adds the required attributes that are missing from the given resource, e.g. multi valued attributes without
+// elements or complex attributes of which none of the sub attributes are filled.
+func (f *Fuzzer) complete(resource map[string]interface{}, c fuzz.Continue) {
+	for _, attribute := range f.schema.Attributes {
+		if attribute.Required && attribute.Mutability != schema.ReadOnly && resource[attribute.Name] == nil {
+			resource[attribute.Name] = wrap(attribute, f.validValue(attribute.Name, attribute, c))
+		}
+	}
+}
+
+// elements returns the complex values of the given (multi valued) attribute value.
+func elements(value interface{}) []map[string]interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{value}
+	case []interface{}:
+		var elements []map[string]interface{}
+		for _, element := range value {
+			if element, ok := element.(map[string]interface{}); ok {
+				elements = append(elements, element)
+			}
+		}
+		return elements
+	default:
+		return nil
+	}
+}
+
+// violate breaks the given rule with the given attribute of the resource.
+func (f *Fuzzer) violate(resource map[string]interface{}, v Violation, t target, c fuzz.Continue) {
+	a := t.attribute
+	switch v {
+	case WrongType:
+		f.container(resource, t, c)[a.Name] = wrap(a, wrongValue(a, c))
+	case MissingRequired:
+		delete(f.container(resource, t, c), a.Name)
+	case UnknownCanonicalValue:
+		f.container(resource, t, c)[a.Name] = wrap(a, "x-"+randAlphaString(c.Rand, 8))
+	case MultiplePrimary:
+		values, _ := resource[t.parent.Name].([]interface{})
+		for len(values) < 2 {
+			values = append(values, f.validValue(t.parent.Name, t.parent, c))
+		}
+		for _, element := range elements(values[:2]) {
+			element[a.Name] = true
+		}
+		resource[t.parent.Name] = values
+	case ReadOnlySet:
+		f.container(resource, t, c)[a.Name] = wrap(a, f.validValue(t.path(), a, c))
+	case DuplicateKey:
+		if _, ok := resource[a.Name]; !ok {
+			resource[a.Name] = wrap(a, f.validValue(t.path(), a, c))
+		}
+		duplicate := strings.ToUpper(a.Name)
+		if duplicate == a.Name {
+			duplicate = strings.ToLower(a.Name)
+		}
+		resource[duplicate] = wrap(a, f.validValue(t.path(), a, c))
+	case OverNesting:
+		f.container(resource, t, c)[a.Name] = map[string]interface{}{
+			a.Name: wrap(a, f.validValue(t.path(), a, c)),
+		}
+	}
+}
+
+// container returns the complex value that contains the given attribute. The (first) value of the parent of a sub
+// attribute is created if it does not exist, with its required sub attributes.
+func (f *Fuzzer) container(resource map[string]interface{}, t target, c fuzz.Continue) map[string]interface{} {
+	if t.parent == nil {
+		return resource
+	}
+	if values := elements(resource[t.parent.Name]); len(values) != 0 {
+		return values[0]
+	}
+	value := f.validValue(t.parent.Name, t.parent, c).(map[string]interface{})
+	if t.parent.MultiValued {
+		resource[t.parent.Name] = []interface{}{value}
+	} else {
+		resource[t.parent.Name] = value
+	}
+	return value
+}
+
+// validValue returns a (single) valid value of the attribute with the given path. Complex values contain the required
+// sub attributes and at least one sub attribute, which are only read-only if the complex attribute is read-only itself.
+func (f *Fuzzer) validValue(path string, attribute *schema.Attribute, c fuzz.Continue) interface{} {
+	path = strings.ToLower(path)
+	if attribute.Type != schema.ComplexType {
+		return f.fuzzSingleAttribute(path, attribute, c)
+	}
+	value := make(map[string]interface{})
+	for _, sub := range attribute.SubAttributes {
+		if sub.Type == schema.ComplexType || (sub.Mutability == schema.ReadOnly && attribute.Mutability != schema.ReadOnly) {
+			continue
+		}
+		if sub.Required || len(value) == 0 {
+			value[sub.Name] = wrap(sub, f.fuzzSingleAttribute(path+"."+strings.ToLower(sub.Name), sub, c))
+		}
+	}
+	return value
+}
+
+// wrap puts the given value in a slice if the attribute is multi valued.
+func wrap(attribute *schema.Attribute, value interface{}) interface{} {
+	if attribute.MultiValued {
+		return []interface{}{value}
+	}
+	return value
+}
+
+// wrongValue returns a value that does not match the type of the given attribute.
+func wrongValue(attribute *schema.Attribute, c fuzz.Continue) interface{} {
+	switch attribute.Type {
+	case schema.BooleanType:
+		return "true"
+	case schema.IntegerType:
+		return 1.5 + float64(c.Rand.Intn(100))
+	case schema.DecimalType:
+		return fmt.Sprint(c.Rand.Float64())
+	case schema.ComplexType:
+		return randAlphaString(c.Rand, 10)
+	default: // string, reference, dateTime and binary values are strings
+		return c.Rand.Intn(1000)
+	}
+}
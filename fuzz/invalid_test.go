@@ -0,0 +1,160 @@
+package fuzz
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/scim2/tools/schema"
+)
+
+func invalidSchema() schema.ReferenceSchema {
+	return schema.ReferenceSchema{
+		Attributes: []*schema.Attribute{
+			{Name: "id", Type: schema.StringType, Required: true, Mutability: schema.ReadOnly},
+			{Name: "userName", Type: schema.StringType, Required: true},
+			{Name: "active", Type: schema.BooleanType},
+			{Name: "userType", Type: schema.StringType, CanonicalValues: []string{"Employee", "Contractor"}},
+			{Name: "name", Type: schema.ComplexType, SubAttributes: []*schema.Attribute{
+				{Name: "givenName", Type: schema.StringType},
+			}},
+			{Name: "emails", Type: schema.ComplexType, MultiValued: true, SubAttributes: []*schema.Attribute{
+				{Name: "value", Type: schema.StringType, Required: true},
+				{Name: "primary", Type: schema.BooleanType},
+			}},
+			{Name: "roles", Type: schema.StringType, MultiValued: true, Required: true},
+			{Name: "manager", Type: schema.ComplexType, Required: true, SubAttributes: []*schema.Attribute{
+				{Name: "displayName", Type: schema.StringType},
+				{Name: "value", Type: schema.StringType},
+			}},
+			{Name: "meta", Type: schema.ComplexType, Mutability: schema.ReadOnly, SubAttributes: []*schema.Attribute{
+				{Name: "created", Type: schema.DateTimeType, Mutability: schema.ReadOnly},
+			}},
+		},
+	}
+}
+
+// lookup returns the value of the attribute with the given path, of the first value of multi valued attributes.
+func lookup(resource map[string]interface{}, path string) (interface{}, bool) {
+	parts := strings.SplitN(path, ".", 2)
+	value, ok := resource[parts[0]]
+	if !ok || len(parts) == 1 {
+		return value, ok
+	}
+	values := elements(value)
+	if len(values) == 0 {
+		return nil, false
+	}
+	value, ok = values[0][parts[1]]
+	return value, ok
+}
+
+func TestFuzzer_FuzzInvalid(t *testing.T) {
+	f := New(invalidSchema()).NumElements(0, 3)
+	for _, v := range Violations {
+		for i := 0; i < 50; i++ {
+			invalid, err := f.FuzzInvalid(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if invalid.Violation != v {
+				t.Fatalf("expected %s, got %s", v, invalid.Violation)
+			}
+			resource := invalid.Resource
+			if _, ok := resource["id"]; ok && v != ReadOnlySet {
+				t.Errorf("%s: read-only id should be left out: %v", v, resource)
+			}
+
+			value, ok := lookup(resource, invalid.Path)
+			switch v {
+			case WrongType:
+				if !ok {
+					t.Fatalf("%s: %s is missing", v, invalid.Path)
+				}
+				switch invalid.Path {
+				case "active", "emails.primary":
+					_, ok = value.(bool)
+				case "name", "emails", "manager":
+					_, ok = value.(map[string]interface{})
+					if _, mV := value.([]interface{}); mV {
+						_, ok = value.([]interface{})[0].(map[string]interface{})
+					}
+				default:
+					_, ok = value.(string)
+				}
+				if ok {
+					t.Errorf("%s: %s has the right type: %v", v, invalid.Path, value)
+				}
+			case MissingRequired:
+				if ok {
+					t.Errorf("%s: %s is present", v, invalid.Path)
+				}
+			case UnknownCanonicalValue:
+				if value != nil && (value == "Employee" || value == "Contractor") {
+					t.Errorf("%s: canonical value %v", v, value)
+				}
+			case MultiplePrimary:
+				var primary int
+				for _, email := range elements(resource["emails"]) {
+					if email["primary"] == true {
+						primary++
+					}
+				}
+				if primary < 2 {
+					t.Errorf("%s: only %d primary values", v, primary)
+				}
+			case ReadOnlySet:
+				if !ok {
+					t.Errorf("%s: %s is missing", v, invalid.Path)
+				}
+			case DuplicateKey:
+				var count int
+				for k := range resource {
+					if strings.EqualFold(k, invalid.Path) {
+						count++
+					}
+				}
+				if count != 2 {
+					t.Errorf("%s: %d keys for %s", v, count, invalid.Path)
+				}
+			case OverNesting:
+				if _, ok := value.(map[string]interface{}); !ok {
+					t.Errorf("%s: %s is not complex: %v", v, invalid.Path, value)
+				}
+			}
+
+			// Apart from the violation, the required attributes are present.
+			for _, path := range []string{"userName", "roles", "manager", "emails.value"} {
+				if _, ok := lookup(resource, path); !ok && path != invalid.Path && !strings.HasPrefix(path, invalid.Path+".") &&
+					(path != "emails.value" || resource["emails"] != nil) {
+					t.Errorf("%s: required %s is missing: %v", v, path, resource)
+				}
+			}
+
+			if v != MultiplePrimary {
+				var primary int
+				for _, email := range elements(resource["emails"]) {
+					if email["primary"] == true {
+						primary++
+					}
+				}
+				if primary > 1 {
+					t.Errorf("%s: %d primary values: %v", v, primary, resource)
+				}
+			}
+		}
+	}
+
+	if _, err := New(schema.ReferenceSchema{
+		Attributes: []*schema.Attribute{{Name: "userName", Type: schema.StringType}},
+	}).FuzzInvalid(MultiplePrimary, ReadOnlySet); err == nil {
+		t.Error("error expected, got none")
+	}
+}
+
+func ExampleFuzzer_FuzzInvalid() {
+	invalid, _ := New(invalidSchema()).Seed(1).FuzzInvalid(WrongType, MissingRequired)
+	fmt.Println(invalid.Violation, invalid.Path, invalid.Violation.SCIMType())
+	// Output:
+	// wrongType name.givenName invalidValue
+}
@@ -19,10 +19,15 @@ func randAlphaString(r *rand.Rand, length int) string {
 	return string(runes)
 }
 
-func randDateTime() string {
-	min := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
-	sec := rand.Int63n(time.Now().Unix()-min) + min
-	return time.Unix(sec, 0).Format(time.RFC3339)
+// The range of the generated dateTime values, fixed so that the values only depend on the source of randomness.
+var (
+	minDateTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	maxDateTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+)
+
+func randDateTime(r *rand.Rand) string {
+	sec := r.Int63n(maxDateTime-minDateTime) + minDateTime
+	return time.Unix(sec, 0).UTC().Format(time.RFC3339)
 }
 
 func randStringFromSlice(r *rand.Rand, strings []string) string {
@@ -0,0 +1,188 @@
+package fuzz
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/scim2/tools/schema"
+)
+
+var (
+	givenNames = []string{
+		"Amelia", "Ava", "Benjamin", "Charlotte", "Daniel", "Elijah", "Emma", "Ethan", "Evelyn", "Harper", "Henry",
+		"Isabella", "James", "Liam", "Lucas", "Mason", "Mia", "Noah", "Olivia", "Sophia", "Theodore", "William",
+	}
+	familyNames = []string{
+		"Anderson", "Brown", "Clark", "Davis", "Garcia", "Harris", "Jackson", "Johnson", "Jones", "Lee", "Lewis",
+		"Martin", "Martinez", "Miller", "Moore", "Robinson", "Smith", "Taylor", "Thomas", "Thompson", "White", "Wilson",
+	}
+	domains = []string{"example.com", "example.org", "example.net"}
+	streets = []string{
+		"Cedar Street", "Elm Street", "Highland Avenue", "Lake Drive", "Main Street", "Maple Avenue", "Oak Lane",
+		"Park Avenue", "Pine Street", "Washington Boulevard",
+	}
+	// cities contain the locality, region and first digits of the postal codes of a city in the US.
+	cities = []struct{ locality, region, postalCode string }{
+		{"Austin", "TX", "787"},
+		{"Boston", "MA", "021"},
+		{"Chicago", "IL", "606"},
+		{"Denver", "CO", "802"},
+		{"Hollywood", "CA", "900"},
+		{"Miami", "FL", "331"},
+		{"New York", "NY", "100"},
+		{"Portland", "OR", "972"},
+		{"Seattle", "WA", "981"},
+	}
+	areaCodes = []string{"201", "212", "303", "312", "415", "503", "512", "617", "206", "305"}
+	locales   = []string{"de-DE", "en-GB", "en-US", "es-ES", "fr-BE", "fr-FR", "it-IT", "ja-JP", "nl-BE", "nl-NL"}
+	timezones = []string{
+		"America/Chicago", "America/Denver", "America/Los_Angeles", "America/New_York", "Asia/Tokyo",
+		"Australia/Sydney", "Europe/Amsterdam", "Europe/Berlin", "Europe/Brussels", "Europe/London", "Europe/Paris",
+	}
+)
+
+// realisticValues contain the generators of realistic values of the attributes of the core schemas (RFC 7643), by the
+// (lower case) paths of the attributes and their type.
+var realisticValues = map[string]struct {
+	typ      schema.Type
+	generate func(r *rand.Rand) interface{}
+}{
+	"username":               {schema.StringType, randUserName},
+	"displayname":            {schema.StringType, randFullName},
+	"name.formatted":         {schema.StringType, randFullName},
+	"name.givenname":         {schema.StringType, randGivenName},
+	"name.middlename":        {schema.StringType, randGivenName},
+	"name.familyname":        {schema.StringType, randFamilyName},
+	"emails.value":           {schema.StringType, randEmail},
+	"phonenumbers.value":     {schema.StringType, randPhoneNumber},
+	"addresses.country":      {schema.StringType, func(r *rand.Rand) interface{} { return "US" }},
+	"locale":                 {schema.StringType, randLocale},
+	"preferredlanguage":      {schema.StringType, randLocale},
+	"timezone":               {schema.StringType, randTimezone},
+	"profileurl":             {schema.ReferenceType, randProfileURL},
+	"x509certificates.value": {schema.BinaryType, randCertificate},
+}
+
+// address is a realistic address in the US.
+type address struct{ streetAddress, locality, region, postalCode string }
+
+// addressValues contain the realistic values of the sub-attributes of an address, by their (lower case) paths. These are
+// all derived from the same address, so that e.g. the locality, region and postal code of an address match.
+var addressValues = map[string]func(a address) string{
+	"addresses.formatted": func(a address) string {
+		return fmt.Sprintf("%s\n%s, %s %s USA", a.streetAddress, a.locality, a.region, a.postalCode)
+	},
+	"addresses.streetaddress": func(a address) string { return a.streetAddress },
+	"addresses.locality":      func(a address) string { return a.locality },
+	"addresses.region":        func(a address) string { return a.region },
+	"addresses.postalcode":    func(a address) string { return a.postalCode },
+}
+
+// Realistic makes the Fuzzer generate plausible values for the well-known attributes of the core schemas (RFC 7643),
+// e.g. names for "name.givenName", email addresses for "emails.value", E.164 numbers for "phoneNumbers.value" and self
+// signed certificates for "x509Certificates.value". The values are taken from built-in word lists and drawn from the
+// source of the Fuzzer, so they are reproducible with Seed. Attributes of which the type does not match are ignored,
+// generators that are set with Generator take precedence.
+func (f *Fuzzer) Realistic() *Fuzzer {
+	f.realistic = true
+	return f
+}
+
+// realisticValue returns a realistic value of the given attribute, if it is known. The sub-attributes of an address are
+// derived from the address of the complex value that is being filled, which is created on first use.
+func (f *Fuzzer) realisticValue(path string, attribute *schema.Attribute, r *rand.Rand) (interface{}, bool) {
+	if value, ok := addressValues[path]; ok {
+		if attribute.Type != schema.StringType || len(attribute.CanonicalValues) != 0 {
+			return nil, false
+		}
+		if f.address == nil {
+			a := randAddress(r)
+			f.address = &a
+		}
+		return value(*f.address), true
+	}
+
+	v, ok := realisticValues[path]
+	if !ok || v.typ != attribute.Type || len(attribute.CanonicalValues) != 0 {
+		return nil, false
+	}
+	return v.generate(r), true
+}
+
+func randGivenName(r *rand.Rand) interface{} {
+	return randStringFromSlice(r, givenNames)
+}
+
+func randFamilyName(r *rand.Rand) interface{} {
+	return randStringFromSlice(r, familyNames)
+}
+
+func randFullName(r *rand.Rand) interface{} {
+	return fmt.Sprintf("%s %s", randGivenName(r), randFamilyName(r))
+}
+
+func randUserName(r *rand.Rand) interface{} {
+	given, family := randStringFromSlice(r, givenNames), randStringFromSlice(r, familyNames)
+	return strings.ToLower(given[:1]+family) + fmt.Sprint(r.Intn(100))
+}
+
+func randEmail(r *rand.Rand) interface{} {
+	given, family := randStringFromSlice(r, givenNames), randStringFromSlice(r, familyNames)
+	return strings.ToLower(fmt.Sprintf("%s.%s@%s", given, family, randStringFromSlice(r, domains)))
+}
+
+// randPhoneNumber returns an E.164 number in the range of fictional US numbers, 555-0100 through 555-0199.
+func randPhoneNumber(r *rand.Rand) interface{} {
+	return fmt.Sprintf("+1%s55501%02d", randStringFromSlice(r, areaCodes), r.Intn(100))
+}
+
+// randAddress returns an address in one of the cities, with a postal code that starts with the digits of the city.
+func randAddress(r *rand.Rand) address {
+	city := cities[r.Intn(len(cities))]
+	return address{
+		streetAddress: fmt.Sprintf("%d %s", 1+r.Intn(9999), randStringFromSlice(r, streets)),
+		locality:      city.locality,
+		region:        city.region,
+		postalCode:    fmt.Sprintf("%s%02d", city.postalCode, r.Intn(100)),
+	}
+}
+
+func randLocale(r *rand.Rand) interface{} {
+	return randStringFromSlice(r, locales)
+}
+
+func randTimezone(r *rand.Rand) interface{} {
+	return randStringFromSlice(r, timezones)
+}
+
+func randProfileURL(r *rand.Rand) interface{} {
+	return fmt.Sprintf("https://%s/profiles/%s", randStringFromSlice(r, domains), randUserName(r))
+}
+
+// randCertificate returns a base64 encoded, self signed certificate of a random name. The key is derived from the
+// given source, which makes the certificate reproducible.
+func randCertificate(r *rand.Rand) interface{} {
+	seed := make([]byte, ed25519.SeedSize)
+	r.Read(seed)
+	key := ed25519.NewKeyFromSeed(seed)
+
+	notBefore, _ := time.Parse(time.RFC3339, randDateTime(r))
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(r.Int63()),
+		Subject:      pkix.Name{CommonName: randFullName(r).(string)},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.AddDate(1, 0, 0),
+	}
+	der, err := x509.CreateCertificate(r, template, template, key.Public(), key)
+	if err != nil {
+		panic(fmt.Sprintf("could not create certificate: %v", err))
+	}
+	return base64.StdEncoding.EncodeToString(der)
+}
@@ -0,0 +1,147 @@
+package fuzz
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/scim2/tools/schema"
+)
+
+func realisticSchema() schema.ReferenceSchema {
+	multiValued := func(name string, t schema.Type, subAttributes ...string) *schema.Attribute {
+		attribute := &schema.Attribute{Name: name, Type: schema.ComplexType, MultiValued: true, Required: true}
+		for _, sub := range subAttributes {
+			attribute.SubAttributes = append(attribute.SubAttributes, &schema.Attribute{Name: sub, Type: t, Required: true})
+		}
+		return attribute
+	}
+	return schema.ReferenceSchema{
+		Attributes: []*schema.Attribute{
+			{Name: "userName", Type: schema.StringType, Required: true},
+			{Name: "displayName", Type: schema.StringType, Required: true},
+			{Name: "name", Type: schema.ComplexType, Required: true, SubAttributes: []*schema.Attribute{
+				{Name: "givenName", Type: schema.StringType, Required: true},
+				{Name: "familyName", Type: schema.StringType, Required: true},
+			}},
+			{Name: "locale", Type: schema.StringType, Required: true},
+			{Name: "timezone", Type: schema.StringType, Required: true},
+			{Name: "preferredLanguage", Type: schema.StringType, Required: true},
+			{Name: "profileUrl", Type: schema.ReferenceType, Required: true},
+			{Name: "title", Type: schema.IntegerType, Required: true}, // type does not match
+			multiValued("emails", schema.StringType, "value"),
+			multiValued("phoneNumbers", schema.StringType, "value"),
+			multiValued("addresses", schema.StringType, "formatted", "streetAddress", "locality", "region", "postalCode", "country"),
+			multiValued("x509Certificates", schema.BinaryType, "value"),
+		},
+	}
+}
+
+func TestFuzzer_Realistic(t *testing.T) {
+	var (
+		userName = regexp.MustCompile(`^[a-z]+\d{1,2}$`)
+		email    = regexp.MustCompile(`^[a-z]+\.[a-z]+@example\.(com|org|net)$`)
+		phone    = regexp.MustCompile(`^\+1\d{3}55501\d{2}$`)
+		name     = regexp.MustCompile(`^[A-Z][a-z]+$`)
+	)
+
+	f := New(realisticSchema()).NumElements(1, 2).Realistic()
+	for i := 0; i < 20; i++ {
+		resource := f.Fuzz()
+		if v := resource["userName"].(string); !userName.MatchString(v) {
+			t.Errorf("unexpected userName: %s", v)
+		}
+		if v := resource["displayName"].(string); len(strings.Fields(v)) != 2 {
+			t.Errorf("unexpected displayName: %s", v)
+		}
+		for _, v := range resource["name"].(map[string]interface{}) {
+			if !name.MatchString(v.(string)) {
+				t.Errorf("unexpected name: %s", v)
+			}
+		}
+		if v := resource["locale"].(string); !strings.Contains(strings.Join(locales, " "), v) {
+			t.Errorf("unexpected locale: %s", v)
+		}
+		if v := resource["timezone"].(string); !strings.Contains(v, "/") {
+			t.Errorf("unexpected timezone: %s", v)
+		}
+		if v := resource["profileUrl"].(string); !strings.HasPrefix(v, "https://example.") {
+			t.Errorf("unexpected profileUrl: %s", v)
+		}
+		if _, ok := resource["title"].(int); !ok {
+			t.Errorf("unexpected title: %v", resource["title"])
+		}
+		for _, e := range resource["emails"].([]interface{}) {
+			if v := e.(map[string]interface{})["value"].(string); !email.MatchString(v) {
+				t.Errorf("unexpected email: %s", v)
+			}
+		}
+		for _, p := range resource["phoneNumbers"].([]interface{}) {
+			if v := p.(map[string]interface{})["value"].(string); !phone.MatchString(v) {
+				t.Errorf("unexpected phone number: %s", v)
+			}
+		}
+		for _, a := range resource["addresses"].([]interface{}) {
+			a := a.(map[string]interface{})
+			if v := a["country"]; v != "US" {
+				t.Errorf("unexpected country: %v", v)
+			}
+			var found bool
+			for _, c := range cities {
+				found = found || c.locality == a["locality"] && c.region == a["region"] &&
+					strings.HasPrefix(a["postalCode"].(string), c.postalCode)
+			}
+			if !found {
+				t.Errorf("locality, region and postal code do not match: %v", a)
+			}
+			formatted := fmt.Sprintf("%s\n%s, %s %s USA", a["streetAddress"], a["locality"], a["region"], a["postalCode"])
+			if v := a["formatted"]; v != formatted {
+				t.Errorf("unexpected formatted address: %q, want %q", v, formatted)
+			}
+		}
+		for _, c := range resource["x509Certificates"].([]interface{}) {
+			der, err := base64.StdEncoding.DecodeString(c.(map[string]interface{})["value"].(string))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := x509.ParseCertificate(der); err != nil {
+				t.Errorf("invalid certificate: %v", err)
+			}
+		}
+	}
+
+	if a, b := New(realisticSchema()).Realistic().Seed(3).Fuzz(), New(realisticSchema()).Realistic().Seed(3).Fuzz(); !reflect.DeepEqual(a, b) {
+		t.Errorf("resources with the same seed differ:\n%v\n%v", a, b)
+	}
+
+	resource := New(realisticSchema()).
+		Generator("userName", func(r *rand.Rand) interface{} { return "di-wu" }).
+		Realistic().
+		Fuzz()
+	if v := resource["userName"]; v != "di-wu" {
+		t.Errorf("generator should take precedence, got %v", v)
+	}
+}
+
+func ExampleFuzzer_Realistic() {
+	s := schema.ReferenceSchema{
+		Attributes: []*schema.Attribute{
+			{Name: "userName", Type: schema.StringType, Required: true},
+			{Name: "name", Type: schema.ComplexType, SubAttributes: []*schema.Attribute{
+				{Name: "givenName", Type: schema.StringType, Required: true},
+				{Name: "familyName", Type: schema.StringType, Required: true},
+			}},
+			{Name: "emails", Type: schema.ComplexType, MultiValued: true, SubAttributes: []*schema.Attribute{
+				{Name: "value", Type: schema.StringType, Required: true},
+			}},
+		},
+	}
+	fmt.Println(New(s).Seed(1).NumElements(1, 1).Realistic().Fuzz())
+	// Output:
+	// map[emails:[map[value:daniel.johnson@example.net]] name:map[familyName:Miller givenName:Charlotte] userName:abrown47]
+}
@@ -0,0 +1,73 @@
+package fuzz
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/scim2/tools/schema"
+)
+
+var seedSchema = schema.ReferenceSchema{
+	Attributes: []*schema.Attribute{
+		{Name: "userName", Type: schema.StringType, Required: true},
+		{Name: "active", Type: schema.BooleanType},
+		{Name: "age", Type: schema.IntegerType},
+		{Name: "score", Type: schema.DecimalType},
+		{Name: "birthday", Type: schema.DateTimeType},
+		{Name: "photo", Type: schema.BinaryType},
+		{
+			Name:        "emails",
+			Type:        schema.ComplexType,
+			MultiValued: true,
+			SubAttributes: []*schema.Attribute{
+				{Name: "type", Type: schema.StringType, CanonicalValues: []string{"work", "home"}},
+				{Name: "value", Type: schema.StringType},
+			},
+		},
+	},
+}
+
+func TestFuzzer_Seed(t *testing.T) {
+	a, b := New(seedSchema).Seed(42), New(seedSchema).Seed(42)
+	for i := 0; i < 10; i++ {
+		if ra, rb := a.Fuzz(), b.Fuzz(); !reflect.DeepEqual(ra, rb) {
+			t.Errorf("resources with the same seed differ:\n%v\n%v", ra, rb)
+		}
+	}
+
+	// Seeding again replays the same resources.
+	first := New(seedSchema).Seed(42).Fuzz()
+	if again := a.Seed(42).Fuzz(); !reflect.DeepEqual(first, again) {
+		t.Errorf("reseeded resource differs:\n%v\n%v", first, again)
+	}
+
+	// The same goes for a source in the same state.
+	c, d := New(seedSchema).RandSource(rand.NewSource(7)), New(seedSchema).RandSource(rand.NewSource(7))
+	if rc, rd := c.Fuzz(), d.Fuzz(); !reflect.DeepEqual(rc, rd) {
+		t.Errorf("resources with the same source differ:\n%v\n%v", rc, rd)
+	}
+
+	var different bool
+	for i := int64(0); i < 10 && !different; i++ {
+		different = !reflect.DeepEqual(first, New(seedSchema).Seed(i).Fuzz())
+	}
+	if !different {
+		t.Error("resources with different seeds are all the same")
+	}
+}
+
+func ExampleFuzzer_Seed() {
+	s := schema.ReferenceSchema{
+		Attributes: []*schema.Attribute{
+			{Name: "userName", Type: schema.StringType, Required: true},
+			{Name: "meta", Type: schema.ComplexType, SubAttributes: []*schema.Attribute{
+				{Name: "created", Type: schema.DateTimeType, Required: true},
+			}},
+		},
+	}
+	fmt.Println(New(s).Seed(1).Fuzz())
+	// Output:
+	// map[meta:map[created:2012-05-11T09:57:53Z] userName:bbuxYMLXqg]
+}
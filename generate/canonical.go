@@ -0,0 +1,65 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scim2/tools/schema"
+)
+
+// isCanonical checks whether a named type with constants gets generated for the canonical values of the attribute.
+func isCanonical(attr *schema.Attribute) bool {
+	if len(attr.CanonicalValues) == 0 {
+		return false
+	}
+	return attr.Type == "" || attr.Type == schema.StringType || attr.Type == schema.ReferenceType
+}
+
+// generateCanonicalTypes generates a named type for every attribute of the struct with the given name that has
+// canonical values. Every type has constants for its canonical values and an IsValid method.
+func (g *StructGenerator) generateCanonicalTypes(name string, attrs []*schema.Attribute) {
+	w := g.w
+
+	for _, attr := range attrs {
+		f := g.field(name, attr)
+		if f.custom || !isCanonical(attr) {
+			continue
+		}
+
+		// get longest name to indent constants.
+		var indent int
+		constants := make([]string, len(attr.CanonicalValues))
+		for i, v := range attr.CanonicalValues {
+			constants[i] = f.canonicalValue(v)
+			if l := len(constants[i]); l > indent {
+				indent = l
+			}
+		}
+
+		w.n()
+		w.lnf("// %s represents the canonical values of the %q attribute.", f.typ, attr.Name)
+		w.lnf("type %s %s", f.typ, f.kind)
+		w.n()
+		w.ln("const (")
+		for i, v := range attr.CanonicalValues {
+			w.in(4).w(constants[i])
+			w.sp(indent - len(constants[i]) + 1)
+			w.lnf("%s = %q", f.typ, v)
+		}
+		w.ln(")")
+		w.n()
+		w.lnf("// IsValid checks whether the %s is one of the canonical values.", f.typ)
+		w.lnf("func (v %s) IsValid() bool {", f.typ)
+		w.in(4).ln("switch v {")
+		w.in(4).lnf("case %s:", strings.Join(constants, ", "))
+		w.in(8).ln("return true")
+		w.in(4).ln("}")
+		w.in(4).ln("return false")
+		w.ln("}")
+	}
+}
+
+// canonicalValue returns the constant of the given canonical value of the attribute represented by the field.
+func (f field) canonicalValue(value string) string {
+	return fmt.Sprintf("%s%s", f.typ, cap(keepAlpha(value)))
+}
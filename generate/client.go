@@ -0,0 +1,258 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// clientFile is the name of the file that contains the generated client.
+const clientFile = "client.go"
+
+// clientImports are the imports of the generated client.
+var clientImports = map[string]string{
+	"bytes":         "bytes",
+	"context":       "context",
+	"encoding/json": "json",
+	"io":            "io",
+	"net/http":      "http",
+	"net/url":       "url",
+	"strconv":       "strconv",
+	"strings":       "strings",
+
+	"github.com/scim2/tools/marshal/messages": "messages",
+}
+
+// clientBase contains the part of the client that does not depend on the resources.
+const clientBase = `// Client is a SCIM client for the resources of this package.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the SCIM service provider at the given base URL, e.g. "https://example.com/scim/v2".
+// If the given HTTP client is nil, http.DefaultClient is used.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// do sends a request with the given body to the given path and returns the resource in the response. The resource is
+// nil if the response has no content.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body map[string]interface{}) (map[string]interface{}, error) {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	u := c.baseURL + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/scim+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/scim+json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var resource map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(&resource); err != nil && (err != io.EOF || resp.StatusCode < 300) {
+			if resp.StatusCode >= 300 {
+				return nil, &Error{Status: resp.StatusCode, Detail: http.StatusText(resp.StatusCode)}
+			}
+			return nil, err
+		}
+	}
+	if resp.StatusCode >= 300 {
+		// The status of the response takes precedence, responses that are not a valid error only get a status.
+		e := &Error{}
+		_ = e.UnmarshalSCIM(resource)
+		e.Status = resp.StatusCode
+		return nil, e
+	}
+	return resource, nil
+}
+
+// list requests the resources at the given path that match the query, and decodes the list response into the given
+// list.
+func (c *Client) list(ctx context.Context, path string, query Query, list unmarshaler) error {
+	values := url.Values{}
+	for k, v := range map[string]string{
+		"filter":             query.Filter,
+		"sortBy":             query.SortBy,
+		"sortOrder":          query.SortOrder,
+		"attributes":         strings.Join(query.Attributes, ","),
+		"excludedAttributes": strings.Join(query.ExcludedAttributes, ","),
+	} {
+		if v != "" {
+			values.Set(k, v)
+		}
+	}
+	if query.StartIndex != 0 {
+		values.Set("startIndex", strconv.Itoa(query.StartIndex))
+	}
+	if query.Count != nil {
+		values.Set("count", strconv.Itoa(*query.Count))
+	}
+	resource, err := c.do(ctx, http.MethodGet, path, values, nil)
+	if err != nil {
+		return err
+	}
+	return list.UnmarshalSCIM(resource)
+}
+`
+
+// generateClient generates the client of the resources of the package.
+func (p *PackageGenerator) generateClient() ([]byte, error) {
+	w := newGenWriter(&bytes.Buffer{})
+	w.w(clientBase)
+
+	for _, g := range p.generators {
+		name := g.resourceName()
+		plural := cap(pluralize(name))
+		client := name + "Client"
+		endpoint := p.endpoint(g)
+
+		schemas := p.schemas(g)
+
+		w.n()
+		w.lnf("// %s returns the client of the %s resources.", plural, name)
+		w.lnf("func (c *Client) %s() *%s {", plural, client)
+		w.in(4).lnf("return &%s{client: c}", client)
+		w.ln("}")
+		w.n()
+		w.lnf("// %s manages the %s resources at %q.", client, name, endpoint)
+		w.lnf("type %s struct {", client)
+		w.in(4).ln("client *Client")
+		w.ln("}")
+		w.n()
+		w.lnf("// %sList is a page of %s resources.", name, name)
+		w.lnf("type %sList = messages.ListResponse[%s]", name, name)
+		w.n()
+		w.lnf("// Get returns the %s with the given id.", name)
+		w.lnf("func (c *%s) Get(ctx context.Context, id string) (*%s, error) {", client, name)
+		w.in(4).lnf("resource, err := c.client.do(ctx, http.MethodGet, %q+url.PathEscape(id), nil, nil)", endpoint+"/")
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("return c.decode(resource)")
+		w.ln("}")
+		w.n()
+		w.lnf("// List returns a page of the %s resources that match the given query, an empty filter matches all.", name)
+		w.lnf("func (c *%s) List(ctx context.Context, query Query) (*%sList, error) {", client, name)
+		w.in(4).lnf("var list %sList", name)
+		w.in(4).lnf("if err := c.client.list(ctx, %q, query, &list); err != nil {", endpoint)
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("return &list, nil")
+		w.ln("}")
+		w.n()
+		w.lnf("// Create creates the given %s, it returns the %s as created by the service provider.", name, name)
+		w.lnf("func (c *%s) Create(ctx context.Context, resource %s) (*%s, error) {", client, name, name)
+		w.in(4).lnf("data, err := encode(resource, %s)", schemas)
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).lnf("created, err := c.client.do(ctx, http.MethodPost, %q, nil, data)", endpoint)
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("return c.decode(created)")
+		w.ln("}")
+		w.n()
+		w.lnf("// Replace replaces the %s with the given id, it returns the %s as replaced by the service provider.", name, name)
+		w.lnf("func (c *%s) Replace(ctx context.Context, id string, resource %s) (*%s, error) {", client, name, name)
+		w.in(4).lnf("data, err := encode(resource, %s)", schemas)
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).lnf("replaced, err := c.client.do(ctx, http.MethodPut, %q+url.PathEscape(id), nil, data)", endpoint+"/")
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("return c.decode(replaced)")
+		w.ln("}")
+		w.n()
+		w.lnf("// Patch modifies the %s with the given id. It returns the modified %s, or nil if the service provider did", name, name)
+		w.ln("// not return it.")
+		w.lnf("func (c *%s) Patch(ctx context.Context, id string, ops []PatchOperation) (*%s, error) {", client, name)
+		w.in(4).ln("data, err := messages.PatchOp{Operations: ops}.MarshalSCIM()")
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).lnf("patched, err := c.client.do(ctx, http.MethodPatch, %q+url.PathEscape(id), nil, data)", endpoint+"/")
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("return c.decode(patched)")
+		w.ln("}")
+		w.n()
+		w.lnf("// Delete deletes the %s with the given id.", name)
+		w.lnf("func (c *%s) Delete(ctx context.Context, id string) error {", client)
+		w.in(4).lnf("_, err := c.client.do(ctx, http.MethodDelete, %q+url.PathEscape(id), nil, nil)", endpoint+"/")
+		w.in(4).ln("return err")
+		w.ln("}")
+		w.n()
+		w.lnf("// decode converts the given resource into a %s, it returns nil if the resource is nil.", name)
+		w.lnf("func (c *%s) decode(resource map[string]interface{}) (*%s, error) {", client, name)
+		w.in(4).ln("if resource == nil {")
+		w.in(8).ln("return nil, nil")
+		w.in(4).ln("}")
+		w.in(4).lnf("var decoded %s", name)
+		w.in(4).ln("if err := decoded.UnmarshalSCIM(resource); err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("return &decoded, nil")
+		w.ln("}")
+	}
+	return generateFile(p.pkg, clientImports, w.writer.(*bytes.Buffer).Bytes())
+}
+
+// endpoint returns the endpoint of the resource of the given generator, relative to the base URL.
+func (p *PackageGenerator) endpoint(g *StructGenerator) string {
+	if endpoint, ok := p.endpoints[g.s.Name]; ok {
+		return "/" + strings.TrimPrefix(endpoint, "/")
+	}
+	return "/" + cap(pluralize(g.resourceName()))
+}
+
+// schemas returns the arguments of encode for the resource of the given generator: the core schema followed by the
+// schemas of its extensions.
+func (p *PackageGenerator) schemas(g *StructGenerator) string {
+	schemas := []string{fmt.Sprintf("%q", g.s.ID)}
+	for _, e := range g.e {
+		schemas = append(schemas, fmt.Sprintf("%q", e.ID))
+	}
+	return strings.Join(schemas, ", ")
+}
+
+// pluralize returns the plural of the given (singular) name, e.g. "User" into "Users" or "Policy" into "Policies".
+func pluralize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "s") || strings.HasSuffix(s, "x") || strings.HasSuffix(s, "ch") || strings.HasSuffix(s, "sh"):
+		return s + "es"
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsAny(s[len(s)-2:len(s)-1], "aeiouAEIOU"):
+		return s[:len(s)-1] + "ies"
+	default:
+		return s + "s"
+	}
+}
@@ -0,0 +1,323 @@
+// Command scimgen generates go structs based on SCIM schemas.
+//
+// The schemas are read from the given JSON files. A file contains a single schema, an array of schemas or a
+// ListResponse as returned by the /Schemas endpoint. One go file is written for every schema that is not used as an
+// extension of another schema.
+//
+// Usage:
+//
+//	scimgen [flags] files...
+//
+// It can be used together with go generate:
+//
+//	//go:generate go run github.com/scim2/tools/generate/cmd/scimgen -ptr -ext User=urn:ietf:params:scim:schemas:extension:enterprise:2.0:User schemas.json
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/scim2/tools/generate"
+	"github.com/scim2/tools/schema"
+)
+
+// nonAlphaNumeric matches the characters that are removed from the names of the resources.
+var nonAlphaNumeric = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+// majorVersion matches the major version suffix of an import path, e.g. "/v2" or the "gopkg.in" style ".v2".
+var majorVersion = regexp.MustCompile(`[/.]v[0-9]+$`)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "scimgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// stringsFlag is a flag that can be set multiple times.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func run(args []string, output io.Writer) error {
+	var (
+		pkg      string
+		out      string
+		ptr      bool
+		codecs   bool
+		validate bool
+		client   bool
+		server   bool
+		ts       bool
+		tags     string
+		types    stringsFlag
+		names    stringsFlag
+		exts     stringsFlag
+	)
+
+	flags := flag.NewFlagSet("scimgen", flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.StringVar(&pkg, "pkg", os.Getenv("GOPACKAGE"), "name of the generated package, defaults to $GOPACKAGE")
+	flags.StringVar(&out, "out", ".", "directory to write the generated files to")
+	flags.BoolVar(&ptr, "ptr", false, "use pointers for attributes that are not required")
+	flags.BoolVar(&codecs, "codecs", false, "generate MarshalSCIM and UnmarshalSCIM methods")
+	flags.BoolVar(&validate, "validate", false, "generate Validate methods")
+	flags.BoolVar(&client, "client", false, "generate a typed client for the resources, implies -codecs")
+	flags.BoolVar(&server, "server", false, "generate http handlers and store interfaces for the resources, implies -codecs")
+	flags.BoolVar(&ts, "ts", false, "also write TypeScript definitions of the resources to <resource>.ts")
+	flags.StringVar(&tags, "tags", "scim", "comma separated list of tags to add, e.g. scim,json,yaml")
+	flags.Var(&types, "type", "custom type of an attribute, e.g. id=github.com/google/uuid.UUID (repeatable)")
+	flags.Var(&names, "name", "name of the field of an attribute, e.g. User.name.givenName=FirstName (repeatable)")
+	flags.Var(&exts, "ext", "extension of a resource, e.g. User=urn:ietf:params:scim:schemas:extension:enterprise:2.0:User (repeatable)")
+	flags.Usage = func() {
+		fmt.Fprintln(output, "usage: scimgen [flags] files...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no schema files given")
+	}
+	if pkg == "" {
+		return errors.New("package name is not set")
+	}
+
+	var schemas []schema.ReferenceSchema
+	for _, name := range flags.Args() {
+		s, err := readSchemas(name)
+		if err != nil {
+			return err
+		}
+		schemas = append(schemas, s...)
+	}
+
+	customTypes, err := parseCustomTypes(types)
+	if err != nil {
+		return err
+	}
+
+	extensions, err := parseExtensions(exts, schemas)
+	if err != nil {
+		return err
+	}
+
+	fieldNames, err := parseNames(names, schemas)
+	if err != nil {
+		return err
+	}
+
+	var generators []generate.StructGenerator
+	for _, s := range schemas {
+		if isExtension(s, exts) {
+			continue
+		}
+
+		g, err := generate.NewStructGenerator(s, extensions[s.Name]...)
+		if err != nil {
+			return fmt.Errorf("%s: %v", s.ID, err)
+		}
+		g.UsePtr(ptr).Codecs(codecs).Validation(validate).CustomTypes(customTypes).Names(fieldNames[s.Name])
+		var keys []string
+		for _, k := range strings.Split(tags, ",") {
+			switch k = strings.TrimSpace(k); k {
+			case "":
+			case "scim":
+				g.SCIMTags(true)
+			case "json":
+				g.JSONTags(true)
+			default:
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) != 0 {
+			g.AddTags(func(a *schema.Attribute) map[string]string {
+				tags := make(map[string]string)
+				for _, k := range keys {
+					tags[k] = a.Name + ",omitempty"
+				}
+				return tags
+			})
+		}
+		generators = append(generators, g)
+
+		if ts {
+			tg, err := generate.NewTypeScriptGenerator(s, extensions[s.Name]...)
+			if err != nil {
+				return fmt.Errorf("%s: %v", s.ID, err)
+			}
+			file, err := tg.GenerateFile()
+			if err != nil {
+				return fmt.Errorf("%s: %v", s.ID, err)
+			}
+			name := strings.ToLower(nonAlphaNumeric.ReplaceAllString(s.Name, "")) + ".ts"
+			if err := ioutil.WriteFile(filepath.Join(out, name), file, 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	p := generate.NewPackageGenerator(pkg, generators...)
+	files, err := p.Client(client).Server(server).Generate()
+	if err != nil {
+		return err
+	}
+	for name, file := range files {
+		if err := ioutil.WriteFile(filepath.Join(out, name), file, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSchemas reads the schemas in the file with the given name. The file can contain a single schema, an array of
+// schemas or a ListResponse.
+func readSchemas(name string) ([]schema.ReferenceSchema, error) {
+	raw, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := strings.TrimSpace(string(raw)); strings.HasPrefix(trimmed, "[") {
+		var schemas []schema.ReferenceSchema
+		if err := json.Unmarshal(raw, &schemas); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		return schemas, nil
+	}
+
+	var list struct {
+		Resources []schema.ReferenceSchema `json:"Resources"`
+	}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	if list.Resources != nil {
+		return list.Resources, nil
+	}
+
+	var s schema.ReferenceSchema
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return []schema.ReferenceSchema{s}, nil
+}
+
+// parseCustomTypes parses custom types in the format "attribute=[import/path.]Type".
+func parseCustomTypes(types []string) ([]generate.CustomType, error) {
+	var customTypes []generate.CustomType
+	for _, t := range types {
+		parts := strings.SplitN(t, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid custom type: %q", t)
+		}
+
+		customType := generate.CustomType{
+			AttrName: parts[0],
+			TypeName: parts[1],
+		}
+		if i := strings.LastIndex(parts[1], "."); i != -1 {
+			customType.PkgPath = parts[1][:i]
+			customType.PkgPrefix = packageName(customType.PkgPath)
+			customType.TypeName = parts[1][i+1:]
+		}
+		customTypes = append(customTypes, customType)
+	}
+	return customTypes, nil
+}
+
+// packageName returns the name of the package with the given import path, the last element of the path without the
+// major version suffix. e.g. "github.com/google/uuid" into "uuid" and "github.com/foo/bar/v2" into "bar"
+func packageName(pkgPath string) string {
+	if trimmed := majorVersion.ReplaceAllString(pkgPath, ""); trimmed != "" {
+		pkgPath = trimmed
+	}
+	return path.Base(pkgPath)
+}
+
+// parseNames parses field names in the format "Resource.path=Name", it returns the names per resource name.
+func parseNames(names []string, schemas []schema.ReferenceSchema) (map[string]map[string]string, error) {
+	fieldNames := make(map[string]map[string]string)
+	for _, n := range names {
+		parts := strings.SplitN(n, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, fmt.Errorf("invalid name: %q", n)
+		}
+		path := strings.SplitN(parts[0], ".", 2)
+		if len(path) != 2 || path[0] == "" || path[1] == "" {
+			return nil, fmt.Errorf("invalid name: %q", n)
+		}
+		if !hasResource(schemas, path[0]) {
+			return nil, fmt.Errorf("resource not found: %s", path[0])
+		}
+		if fieldNames[path[0]] == nil {
+			fieldNames[path[0]] = make(map[string]string)
+		}
+		fieldNames[path[0]][path[1]] = parts[1]
+	}
+	return fieldNames, nil
+}
+
+// parseExtensions parses extensions in the format "Resource=urn", it returns the extension schemas per resource name.
+func parseExtensions(exts []string, schemas []schema.ReferenceSchema) (map[string][]schema.ReferenceSchema, error) {
+	extensions := make(map[string][]schema.ReferenceSchema)
+	for _, e := range exts {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid extension: %q", e)
+		}
+		if !hasResource(schemas, parts[0]) {
+			return nil, fmt.Errorf("resource not found: %s", parts[0])
+		}
+
+		var found bool
+		for _, s := range schemas {
+			if strings.EqualFold(s.ID, parts[1]) {
+				extensions[parts[0]] = append(extensions[parts[0]], s)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("extension schema not found: %s", parts[1])
+		}
+	}
+	return extensions, nil
+}
+
+// hasResource checks whether one of the given schemas has the given resource name.
+func hasResource(schemas []schema.ReferenceSchema, name string) bool {
+	for _, s := range schemas {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isExtension checks whether the given schema is used as an extension.
+func isExtension(s schema.ReferenceSchema, exts []string) bool {
+	for _, e := range exts {
+		if parts := strings.SplitN(e, "=", 2); len(parts) == 2 && strings.EqualFold(s.ID, parts[1]) {
+			return true
+		}
+	}
+	return false
+}
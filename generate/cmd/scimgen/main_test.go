@@ -0,0 +1,193 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	out := t.TempDir()
+	if err := run([]string{
+		"-pkg", "scim",
+		"-out", out,
+		"-ptr",
+		"-validate",
+		"-tags", "scim,json",
+		"-type", "id=github.com/google/uuid.UUID",
+		"-name", "User.name.givenName=FirstName",
+		"-name", "User.urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:employeeNumber=Number",
+		"-ext", "User=urn:ietf:params:scim:schemas:extension:enterprise:2.0:User",
+		filepath.Join("testdata", "user.json"),
+		filepath.Join("testdata", "enterprise.json"),
+	}, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ioutil.ReadDir(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Name() != "shared.go" || files[1].Name() != "user.go" {
+		t.Fatalf("expected shared.go and user.go, got %v", files)
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(out, "user.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := string(raw)
+	for _, s := range []string{
+		"package scim",
+		`"github.com/google/uuid"`,
+		`ID               uuid.UUID             ` + "`scim:\"id\" json:\"id,omitempty\"`",
+		"`scim:\"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,inline\"",
+		"DisplayName      *string",
+		"EnterpriseUser EnterpriseUserExtension",
+		"type EnterpriseUserExtension struct {",
+		"Meta             *Meta",
+		"func (v User) Validate() error {",
+		"FirstName ",
+		"Number ",
+	} {
+		if !strings.Contains(file, s) {
+			t.Errorf("expected %q in generated file", s)
+		}
+	}
+}
+
+func TestRun_TypeScript(t *testing.T) {
+	out := t.TempDir()
+	if err := run([]string{
+		"-pkg", "scim",
+		"-out", out,
+		"-ts",
+		"-ext", "User=urn:ietf:params:scim:schemas:extension:enterprise:2.0:User",
+		filepath.Join("testdata", "user.json"),
+		filepath.Join("testdata", "enterprise.json"),
+		filepath.Join("testdata", "group.json"),
+	}, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(out, "user.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := string(raw)
+	for _, s := range []string{
+		"export interface User {",
+		"userName: string;",
+		"readonly id: string;",
+		`"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"?: EnterpriseUserExtension;`,
+		"export interface EnterpriseUserExtension {",
+	} {
+		if !strings.Contains(file, s) {
+			t.Errorf("expected %q in user.ts", s)
+		}
+	}
+	if _, err := ioutil.ReadFile(filepath.Join(out, "group.ts")); err != nil {
+		t.Error(err)
+	}
+	if _, err := ioutil.ReadFile(filepath.Join(out, "enterpriseuser.ts")); err == nil {
+		t.Error("unexpected definitions of the extension")
+	}
+}
+
+func TestRun_ListResponse(t *testing.T) {
+	dir := t.TempDir()
+	list := filepath.Join(dir, "schemas.json")
+	if err := ioutil.WriteFile(list, []byte(`{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:ListResponse"],
+		"totalResults": 2,
+		"Resources": [
+			{"id": "urn:ietf:params:scim:schemas:core:2.0:User", "name": "User", "attributes": []},
+			{"id": "urn:ietf:params:scim:schemas:core:2.0:Group", "name": "Group", "attributes": []}
+		]
+	}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run([]string{"-pkg", "scim", "-out", dir, list}, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"user.go", "group.go"} {
+		if _, err := ioutil.ReadFile(filepath.Join(dir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"-pkg", "scim"},
+		{list},
+		{"-pkg", "scim", "-type", "id", list},
+		{"-pkg", "scim", "-ext", "User=urn:unknown", list},
+		{"-pkg", "scim", "-out", dir, "-name", "userName=Name", list},
+		{"-pkg", "scim", "-out", dir, "-name", "User.userName=user-name", list},
+		{"-pkg", "scim", "-out", dir, "-name", "Userr.userName=Name", list},
+		{"-pkg", "scim", "-out", dir, "-ext", "Userr=urn:ietf:params:scim:schemas:core:2.0:Group", list},
+	} {
+		if err := run(args, ioutil.Discard); err == nil {
+			t.Errorf("%v: error expected, got none", args)
+		}
+	}
+}
+
+func TestParseCustomTypes(t *testing.T) {
+	for pkgPath, prefix := range map[string]string{
+		"github.com/google/uuid":     "uuid",
+		"github.com/example/foo/v2":  "foo",
+		"gopkg.in/example/yaml.v3":   "yaml",
+		"github.com/example/v2":      "example",
+		"github.com/example/v2/uuid": "uuid",
+	} {
+		types, err := parseCustomTypes([]string{"id=" + pkgPath + ".ID"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ct := types[0]; ct.PkgPath != pkgPath || ct.PkgPrefix != prefix || ct.TypeName != "ID" {
+			t.Errorf("%s: unexpected custom type: %+v", pkgPath, ct)
+		}
+	}
+}
+
+// TestRun_Generated checks whether the generated files in internal/resources are up to date.
+func TestRun_Generated(t *testing.T) {
+	dir := filepath.Join("..", "..", "internal", "resources")
+	doc, err := ioutil.ReadFile(filepath.Join(dir, "doc.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var args []string
+	for _, line := range strings.Split(string(doc), "\n") {
+		if prefix := "//go:generate go run ../../cmd/scimgen "; strings.HasPrefix(line, prefix) {
+			args = strings.Fields(strings.TrimPrefix(line, prefix))
+		}
+	}
+	if len(args) == 0 {
+		t.Fatal("go:generate directive not found")
+	}
+
+	out := t.TempDir()
+	for i, arg := range args {
+		if strings.HasSuffix(arg, ".json") {
+			args[i] = filepath.Join(dir, arg)
+		}
+	}
+	if err := run(append([]string{"-out", out}, args...), ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ioutil.ReadDir(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		generated, _ := ioutil.ReadFile(filepath.Join(out, f.Name()))
+		current, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil || string(generated) != string(current) {
+			t.Errorf("%s is not up to date, run go generate ./...", f.Name())
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scim2/tools/schema"
+)
+
+// simpleCases contains the cases of the type switch that converts a SCIM value to the given go type.
+// The conversion and (optional) invalid formats contain %[1]s verbs which get replaced with the value.
+var simpleCases = map[string][]struct {
+	typ, conversion, invalid string
+}{
+	"string": {
+		{"string", "%s", ""},
+	},
+	"bool": {
+		{"bool", "%s", ""},
+	},
+	"int": {
+		{"int", "%s", ""},
+		{"int64", "int(%s)", ""},
+		{"float64", "int(%[1]s)", "%[1]s != float64(int(%[1]s))"},
+	},
+	"float64": {
+		{"float64", "%s", ""},
+		{"int", "float64(%s)", ""},
+		{"int64", "float64(%s)", ""},
+	},
+}
+
+// zeroValues contains the zero values of the simple go types.
+var zeroValues = map[string]string{
+	"string":  `""`,
+	"bool":    "false",
+	"int":     "0",
+	"float64": "0",
+}
+
+// generateCodecs generates the MarshalSCIM and UnmarshalSCIM methods of the struct with the given name.
+func (g *StructGenerator) generateCodecs(name string, attrs []*schema.Attribute, core bool) {
+	w := g.w
+
+	fields := make([]field, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = append(fields, g.field(name, attr))
+	}
+	if core {
+		for _, e := range g.e {
+			extensionName := g.extensionName(e)
+			fields = append(fields, field{
+				attr: &schema.Attribute{
+					Name: e.ID,
+					Type: schema.ComplexType,
+				},
+				name: extensionName,
+				typ:  extensionName + "Extension",
+			})
+		}
+	}
+
+	w.n()
+	w.lnf("// MarshalSCIM converts the %s into a SCIM resource.", name)
+	w.lnf("func (v %s) MarshalSCIM() (map[string]interface{}, error) {", name)
+	w.in(4).ln("resource := make(map[string]interface{})")
+	for _, f := range fields {
+		g.generateMarshalField(f)
+	}
+	w.in(4).ln("return resource, nil")
+	w.ln("}")
+
+	w.n()
+	w.lnf("// UnmarshalSCIM fills the %s with the given SCIM resource.", name)
+	w.lnf("func (v *%s) UnmarshalSCIM(resource map[string]interface{}) error {", name)
+	for _, f := range fields {
+		g.generateUnmarshalField(f)
+	}
+	w.in(4).ln("return nil")
+	w.ln("}")
+}
+
+// isComplex checks whether the field gets converted with its own MarshalSCIM and UnmarshalSCIM methods.
+func (f field) isComplex() bool {
+	return f.attr.Type == schema.ComplexType
+}
+
+// isSimple checks whether the type of the field is a (non custom) simple go type, or a type based on one.
+func (f field) isSimple() bool {
+	_, ok := simpleCases[f.kind]
+	return ok
+}
+
+// isEncoded checks whether the type of the field is a dateTime (time.Time) or binary ([]byte) type, of which the values
+// are represented by strings in SCIM resources.
+func (f field) isEncoded() bool {
+	return f.kind == "time.Time" || f.kind == "[]byte"
+}
+
+// encodedValue converts the given value to the value that gets stored in the SCIM resource.
+func (g *StructGenerator) encodedValue(f field, value string) string {
+	switch f.kind {
+	case "time.Time":
+		return fmt.Sprintf("%s.Format(time.RFC3339)", value)
+	case "[]byte":
+		g.addImport("encoding/base64", "base64")
+		return fmt.Sprintf("base64.StdEncoding.EncodeToString(%s)", value)
+	default:
+		return f.simpleValue(value)
+	}
+}
+
+// simpleValue converts the given value to the underlying simple go type of the field, values of custom types are
+// returned as is.
+func (f field) simpleValue(value string) string {
+	if f.kind != "" && f.typ != f.kind {
+		return fmt.Sprintf("%s(%s)", f.kind, value)
+	}
+	return value
+}
+
+// varName returns the name of a local variable for the value of the field.
+func (f field) varName() string {
+	return lowerFirst(f.name) + "Value"
+}
+
+func (g *StructGenerator) generateMarshalField(f field) {
+	w := g.w.in(4)
+	key := fmt.Sprintf("%q", f.attr.Name)
+
+	switch {
+	case f.attr.MultiValued:
+		w.lnf("if v.%s != nil {", f.name)
+		w.in(4).lnf("values := make([]interface{}, 0, len(v.%s))", f.name)
+		w.in(4).lnf("for _, value := range v.%s {", f.name)
+		if f.isComplex() {
+			w.in(8).ln("element, err := value.MarshalSCIM()")
+			w.in(8).ln("if err != nil {")
+			w.in(12).ln("return nil, err")
+			w.in(8).ln("}")
+			w.in(8).ln("values = append(values, element)")
+		} else {
+			w.in(8).lnf("values = append(values, %s)", g.encodedValue(f, "value"))
+		}
+		w.in(4).ln("}")
+		w.in(4).lnf("resource[%s] = values", key)
+		w.ln("}")
+	case f.isComplex():
+		v := f.varName()
+		if f.ptr {
+			w.lnf("if v.%s != nil {", f.name)
+			w = w.in(4)
+		}
+		w.lnf("%s, err := v.%s.MarshalSCIM()", v, f.name)
+		w.ln("if err != nil {")
+		w.in(4).ln("return nil, err")
+		w.ln("}")
+		if f.ptr {
+			// A pointer to an empty struct clears the attribute.
+			w.lnf("if len(%s) == 0 {", v)
+			w.in(4).lnf("resource[%s] = nil", key)
+			w.ln("} else {")
+			w.in(4).lnf("resource[%s] = %s", key, v)
+			w.ln("}")
+			g.w.in(4).ln("}")
+		} else {
+			w.lnf("if len(%s) != 0 {", v)
+			w.in(4).lnf("resource[%s] = %s", key, v)
+			w.ln("}")
+		}
+	case f.ptr:
+		w.lnf("if v.%s != nil {", f.name)
+		if f.isSimple() {
+			w.in(4).lnf("resource[%s] = %s", key, f.simpleValue("*v."+f.name))
+		} else if f.isEncoded() {
+			w.in(4).lnf("resource[%s] = %s", key, g.encodedValue(f, "v."+f.name))
+		} else {
+			w.in(4).lnf("resource[%s] = *v.%s", key, f.name)
+		}
+		w.ln("}")
+	case f.isSimple():
+		if f.kind == "bool" {
+			w.lnf("if v.%s {", f.name)
+		} else {
+			w.lnf("if v.%s != %s {", f.name, zeroValues[f.kind])
+		}
+		w.in(4).lnf("resource[%s] = %s", key, f.simpleValue("v."+f.name))
+		w.ln("}")
+	case f.isEncoded():
+		if f.kind == "time.Time" {
+			w.lnf("if !v.%s.IsZero() {", f.name)
+		} else {
+			w.lnf("if len(v.%s) != 0 {", f.name)
+		}
+		w.in(4).lnf("resource[%s] = %s", key, g.encodedValue(f, "v."+f.name))
+		w.ln("}")
+	default:
+		w.lnf("resource[%s] = v.%s", key, f.name)
+	}
+}
+
+func (g *StructGenerator) generateUnmarshalField(f field) {
+	w := g.w.in(4)
+	key := fmt.Sprintf("%q", f.attr.Name)
+
+	if f.attr.MultiValued {
+		w.lnf("switch values := resource[%s].(type) {", key)
+		w.ln("case nil:")
+		w.ln("case []interface{}:")
+		w.in(4).lnf("v.%s = make([]%s, 0, len(values))", f.name, f.typ)
+		w.in(4).ln("for _, value := range values {")
+		g.generateUnmarshalValue(w.in(8), f, func(w *genWriter, value string) {
+			w.lnf("v.%s = append(v.%s, %s)", f.name, f.name, value)
+		})
+		w.in(4).ln("}")
+		w.ln("default:")
+		w.in(4).lnf("return fmt.Errorf(%q, values)", fmt.Sprintf("types of %q do not match: got %%T, want []%s", f.attr.Name, f.typ))
+		w.ln("}")
+		return
+	}
+
+	g.generateUnmarshalValue(w, f, func(w *genWriter, value string) {
+		if f.ptr {
+			w.lnf("%s := %s", f.varName(), value)
+			w.lnf("v.%s = &%s", f.name, f.varName())
+		} else {
+			w.lnf("v.%s = %s", f.name, value)
+		}
+	})
+}
+
+// generateUnmarshalValue generates a type switch that converts the value of the attribute of the given field. The
+// assign function is called with the converted value.
+func (g *StructGenerator) generateUnmarshalValue(w *genWriter, f field, assign func(w *genWriter, value string)) {
+	value := fmt.Sprintf("resource[%q]", f.attr.Name)
+	if f.attr.MultiValued {
+		value = "value"
+	}
+
+	w.lnf("switch value := %s.(type) {", value)
+	if !f.attr.MultiValued {
+		w.ln("case nil:")
+		if f.isComplex() && f.ptr {
+			// An explicit null is converted to a pointer to an empty struct, which gets marshaled to null again.
+			w.in(4).lnf("if _, ok := resource[%q]; ok {", f.attr.Name)
+			w.in(8).lnf("v.%s = new(%s)", f.name, f.typ)
+			w.in(4).ln("}")
+		}
+	}
+	switch {
+	case f.isComplex():
+		w.ln("case map[string]interface{}:")
+		w.in(4).lnf("var element %s", f.typ)
+		w.in(4).ln("if err := element.UnmarshalSCIM(value); err != nil {")
+		w.in(8).ln("return err")
+		w.in(4).ln("}")
+		assign(w.in(4), "element")
+	case f.isSimple():
+		for _, c := range simpleCases[f.kind] {
+			w.lnf("case %s:", c.typ)
+			if c.invalid != "" {
+				w.in(4).lnf("if %s {", fmt.Sprintf(c.invalid, "value"))
+				w.in(8).lnf("return fmt.Errorf(%q, value)", fmt.Sprintf("value of %q is not a valid %s: %%v", f.attr.Name, f.kind))
+				w.in(4).ln("}")
+			}
+			value := fmt.Sprintf(c.conversion, "value")
+			if f.typ != f.kind {
+				value = fmt.Sprintf("%s(%s)", f.typ, value)
+			}
+			assign(w.in(4), value)
+		}
+	case f.isEncoded():
+		w.ln("case string:")
+		if f.kind == "time.Time" {
+			w.in(4).ln("parsed, err := time.Parse(time.RFC3339, value)")
+		} else {
+			w.in(4).ln("parsed, err := base64.StdEncoding.DecodeString(value)")
+			g.addImport("encoding/base64", "base64")
+		}
+		w.in(4).ln("if err != nil {")
+		w.in(8).lnf("return fmt.Errorf(%q, err)", fmt.Sprintf("value of %q is not a valid %s: %%v", f.attr.Name, f.attr.Type))
+		w.in(4).ln("}")
+		assign(w.in(4), "parsed")
+		w.lnf("case %s:", f.kind)
+		assign(w.in(4), "value")
+	default:
+		w.lnf("case %s:", f.typ)
+		assign(w.in(4), "value")
+		if f.custom {
+			g.generateUnmarshalCustom(w, f, assign)
+		}
+	}
+	want := f.typ
+	if f.isComplex() {
+		want = "map[string]interface{}"
+	} else if f.isSimple() {
+		want = f.kind
+	} else if f.isEncoded() {
+		want = "string"
+	}
+	w.ln("default:")
+	w.in(4).lnf("return fmt.Errorf(%q, value)", fmt.Sprintf("types of %q do not match: got %%T, want %s", f.attr.Name, want))
+	w.ln("}")
+}
+
+// primitiveTypes are the go types of the simple values in a decoded SCIM resource.
+var primitiveTypes = []string{"string", "float64", "bool"}
+
+// generateUnmarshalCustom generates the case that converts a simple SCIM value to the custom type of the field, the
+// value gets converted through its JSON representation, e.g. with the UnmarshalText or UnmarshalJSON method of the type.
+func (g *StructGenerator) generateUnmarshalCustom(w *genWriter, f field, assign func(w *genWriter, value string)) {
+	var cases []string
+	for _, t := range primitiveTypes {
+		if t != f.typ {
+			cases = append(cases, t)
+		}
+	}
+	g.addImport("encoding/json", "json")
+	w.lnf("case %s:", strings.Join(cases, ", "))
+	w.in(4).ln("raw, _ := json.Marshal(value)")
+	w.in(4).lnf("var element %s", f.typ)
+	w.in(4).ln("if err := json.Unmarshal(raw, &element); err != nil {")
+	w.in(8).lnf("return fmt.Errorf(%q, err)", fmt.Sprintf("value of %q is not a valid %s: %%v", f.attr.Name, f.typ))
+	w.in(4).ln("}")
+	assign(w.in(4), "element")
+}
+
+// lowerFirst lowers the first character of the given string, or the whole string if it is in upper case.
+// e.g. "UserName" into "userName" or "ID" into "id"
+func lowerFirst(s string) string {
+	if s == "" || strings.ToUpper(s) == s {
+		return strings.ToLower(s)
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -50,10 +50,10 @@ func (w *genWriter) sp(n int) error {
 	return w.w(strings.Repeat(" ", n))
 }
 
-// in adds n spaces as before the prefix.
+// in returns a writer that prepends n spaces to every write, nested writers add their spaces before the outer prefix.
 func (w *genWriter) in(n int) *genWriter {
 	return &genWriter{
 		writer: w,
-		prefix: strings.Repeat(" ", n) + w.prefix,
+		prefix: strings.Repeat(" ", n),
 	}
 }
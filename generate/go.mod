@@ -1,5 +1,10 @@
 module github.com/scim2/tools/generate
 
-go 1.15
+go 1.18
 
-require github.com/scim2/tools/schema v1.0.0
+require (
+	github.com/scim2/tools/marshal v1.2.0
+	github.com/scim2/tools/schema v1.1.0
+)
+
+require github.com/scim2/tools/attributes v1.0.0 // indirect
@@ -0,0 +1,37 @@
+package generate_test
+
+import (
+	"testing"
+
+	"github.com/scim2/tools/generate"
+	"github.com/scim2/tools/generate/internal/golden"
+	"github.com/scim2/tools/schema"
+)
+
+func TestStructGenerator_golden(t *testing.T) {
+	golden.Run(t, "testdata/golden", ".go", func(s schema.ReferenceSchema, extensions ...schema.ReferenceSchema) ([]byte, error) {
+		g, err := generate.NewStructGenerator(s, extensions...)
+		if err != nil {
+			return nil, err
+		}
+		g.Package("resources").UsePtr(true).Codecs(true).Validation(true).SCIMTags(true).
+			CustomTypes([]generate.CustomType{
+				{PkgPrefix: "netip", PkgPath: "net/netip", AttrName: "gateway", TypeName: "Addr"},
+				{PkgPrefix: "netip", PkgPath: "net/netip", AttrName: "ipAddresses", TypeName: "Addr"},
+			}).
+			AddTags(func(a *schema.Attribute) map[string]string {
+				return map[string]string{"json": a.Name + ",omitempty"}
+			})
+		return g.GenerateFile()
+	})
+}
+
+func TestTypeScriptGenerator_golden(t *testing.T) {
+	golden.Run(t, "testdata/golden", ".ts", func(s schema.ReferenceSchema, extensions ...schema.ReferenceSchema) ([]byte, error) {
+		g, err := generate.NewTypeScriptGenerator(s, extensions...)
+		if err != nil {
+			return nil, err
+		}
+		return g.GenerateFile()
+	})
+}
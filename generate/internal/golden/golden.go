@@ -0,0 +1,147 @@
+// Package golden tests the output of the generators against golden files.
+//
+// Every fixture is a JSON file in the test data directory that contains a schema, or an array of schemas of which the
+// first one is the resource and the others its extensions. The output of a generator for a fixture is compared with the
+// golden file next to it, the file with the same name but the extension of the generated code, e.g. "user.json" and
+// "user.go". Generated go files also have to type check.
+//
+// The golden files are (re)written by running the tests with the update flag:
+//
+//	go test -run _golden -update
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scim2/tools/schema"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// Generator generates code for the given resource schema and its extensions.
+type Generator func(s schema.ReferenceSchema, extensions ...schema.ReferenceSchema) ([]byte, error)
+
+// Run runs the generator over every fixture in the given directory and compares the output with the golden files with
+// the given extension, e.g. ".go" or ".ts".
+func Run(t *testing.T, dir, ext string, generate Generator) {
+	t.Helper()
+
+	fixtures, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatalf("no fixtures found in %s", dir)
+	}
+
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".json")
+		t.Run(name, func(t *testing.T) {
+			schemas, err := ReadFixture(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := generate(schemas[0], schemas[1:]...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ext == ".go" {
+				if err := TypeCheck(map[string][]byte{name + ext: actual}); err != nil {
+					t.Errorf("generated code does not type check: %v", err)
+				}
+			}
+
+			file := strings.TrimSuffix(fixture, ".json") + ext
+			if *update {
+				if err := os.WriteFile(file, actual, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("%v, run the tests with -update to create the golden file", err)
+			}
+			if !bytes.Equal(expected, actual) {
+				t.Errorf("output does not match %s, run the tests with -update to update it:\n%s", file, diff(expected, actual))
+			}
+		})
+	}
+}
+
+// ReadFixture reads the schemas of the fixture with the given file name.
+func ReadFixture(name string) ([]schema.ReferenceSchema, error) {
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		var schemas []schema.ReferenceSchema
+		if err := json.Unmarshal(raw, &schemas); err != nil {
+			return nil, err
+		}
+		if len(schemas) == 0 {
+			return nil, fmt.Errorf("%s: empty array of schemas", name)
+		}
+		return schemas, nil
+	}
+	var s schema.ReferenceSchema
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	return []schema.ReferenceSchema{s}, nil
+}
+
+// TypeCheck parses and type checks the given go files, mapped by their names, as a single package. Only packages of
+// the standard library can be imported.
+func TypeCheck(files map[string][]byte) error {
+	fset := token.NewFileSet()
+	parsed := make([]*ast.File, 0, len(files))
+	for name, src := range files {
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err := conf.Check(parsed[0].Name.Name, fset, parsed, nil)
+	return err
+}
+
+// diff returns the first line that differs between the expected and the actual output, with the lines before it.
+func diff(expected, actual []byte) string {
+	e, a := strings.Split(string(expected), "\n"), strings.Split(string(actual), "\n")
+	line := func(lines []string, i int) string {
+		if i < len(lines) {
+			return lines[i]
+		}
+		return "<EOF>"
+	}
+	for i := 0; i < len(e) || i < len(a); i++ {
+		if line(e, i) == line(a, i) {
+			continue
+		}
+		var b strings.Builder
+		for j := i - 3; j < i; j++ {
+			if j >= 0 {
+				fmt.Fprintf(&b, "  %s\n", e[j])
+			}
+		}
+		fmt.Fprintf(&b, "- %s\n+ %s\n", line(e, i), line(a, i))
+		return b.String()
+	}
+	return ""
+}
@@ -0,0 +1,205 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scim2/tools/marshal"
+	"github.com/scim2/tools/marshal/messages"
+)
+
+// testServer is a minimal SCIM service provider that stores the users in memory.
+type testServer struct {
+	t        *testing.T
+	users    map[string]map[string]interface{}
+	requests []map[string]interface{}
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/scim+json" {
+		s.t.Errorf("unexpected accept header: %q", r.Header.Get("Accept"))
+	}
+
+	write := func(status int, resource interface{}) {
+		w.Header().Set("Content-Type", "application/scim+json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resource)
+	}
+
+	var body map[string]interface{}
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			// The handler does not run on the goroutine of the test, so the test can not be stopped here.
+			s.t.Error(err)
+			write(http.StatusBadRequest, map[string]interface{}{"status": "400", "scimType": "invalidSyntax", "detail": err.Error()})
+			return
+		}
+		s.requests = append(s.requests, body)
+	}
+	notFound := func() {
+		write(http.StatusNotFound, map[string]interface{}{
+			"schemas": []string{"urn:ietf:params:scim:api:messages:2.0:Error"},
+			"status":  "404",
+			"detail":  "resource not found",
+		})
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/Users/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/Users":
+		if r.URL.Query().Get("filter") == "invalid" {
+			write(http.StatusBadRequest, map[string]interface{}{"status": "400", "scimType": "invalidFilter", "detail": "invalid filter"})
+			return
+		}
+		if q := r.URL.Query(); q.Get("startIndex") != "1" || q.Get("count") != "10" || q.Get("attributes") != "userName,emails" {
+			s.t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		var resources []interface{}
+		for _, u := range s.users {
+			resources = append(resources, u)
+		}
+		write(http.StatusOK, map[string]interface{}{
+			"schemas":      []string{"urn:ietf:params:scim:api:messages:2.0:ListResponse"},
+			"totalResults": len(resources),
+			"itemsPerPage": 10,
+			"startIndex":   1,
+			"Resources":    resources,
+		})
+	case r.Method == http.MethodPost && r.URL.Path == "/Users":
+		body["id"] = "0001"
+		s.users["0001"] = body
+		write(http.StatusCreated, body)
+	case s.users[id] == nil:
+		notFound()
+	case r.Method == http.MethodGet:
+		write(http.StatusOK, s.users[id])
+	case r.Method == http.MethodPut:
+		body["id"] = id
+		s.users[id] = body
+		write(http.StatusOK, body)
+	case r.Method == http.MethodPatch:
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		delete(s.users, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		notFound()
+	}
+}
+
+func TestClient(t *testing.T) {
+	s := &testServer{t: t, users: make(map[string]map[string]interface{})}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	ctx := context.Background()
+	users := NewClient(server.URL+"/", server.Client()).Users()
+
+	userName, employeeNumber := "di-wu", "1"
+	created, err := users.Create(ctx, User{
+		UserName: userName,
+		Emails:   []UserEmail{{Value: &userName}},
+		EnterpriseUser: EnterpriseUserExtension{
+			EmployeeNumber: &employeeNumber,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "0001" || created.UserName != userName || *created.EnterpriseUser.EmployeeNumber != "1" {
+		t.Errorf("unexpected user: %+v", created)
+	}
+	schemas, _ := s.requests[0]["schemas"].([]interface{})
+	if len(schemas) != 2 || schemas[1] != "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User" {
+		t.Errorf("unexpected schemas: %v", schemas)
+	}
+
+	user, err := users.Get(ctx, "0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.UserName != userName || len(user.Emails) != 1 {
+		t.Errorf("unexpected user: %+v", user)
+	}
+
+	count := 10
+	list, err := users.List(ctx, Query{
+		Filter:     `userName eq "di-wu"`,
+		Attributes: []string{"userName", "emails"},
+		StartIndex: 1,
+		Count:      &count,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.TotalResults != 1 || list.ItemsPerPage != 10 || len(list.Resources) != 1 || list.Resources[0].ID != "0001" {
+		t.Errorf("unexpected list: %+v", list)
+	}
+
+	user.UserName = "quint"
+	replaced, err := users.Replace(ctx, "0001", *user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replaced.UserName != "quint" {
+		t.Errorf("unexpected user: %+v", replaced)
+	}
+
+	patched, err := users.Patch(ctx, "0001", []PatchOperation{
+		{Op: "replace", Path: "name", Value: UserName{GivenName: &userName}},
+		{Op: "remove", Path: "nickName"},
+	})
+	if err != nil || patched != nil {
+		t.Errorf("unexpected patch result: %v, %v", patched, err)
+	}
+	ops, _ := s.requests[len(s.requests)-1]["Operations"].([]interface{})
+	if len(ops) != 2 || ops[0].(map[string]interface{})["value"].(map[string]interface{})["givenName"] != userName {
+		t.Errorf("unexpected operations: %v", ops)
+	}
+
+	if err := users.Delete(ctx, "0001"); err != nil {
+		t.Fatal(err)
+	}
+
+	var scimErr *Error
+	if _, err := users.Get(ctx, "0001"); !errors.As(err, &scimErr) || scimErr.Status != http.StatusNotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if _, err := users.List(ctx, Query{Filter: "invalid"}); !errors.As(err, &scimErr) || scimErr.SCIMType != "invalidFilter" {
+		t.Errorf("expected invalid filter error, got %v", err)
+	}
+}
+
+// TestMessages checks whether the messages of the client and server are those of the marshal/messages package.
+func TestMessages(t *testing.T) {
+	list := UserList{TotalResults: 1, Resources: []User{{ID: "0001", UserName: "di-wu"}}}
+	data, err := marshal.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resource map[string]interface{}
+	if err := json.Unmarshal(raw, &resource); err != nil {
+		t.Fatal(err)
+	}
+	var decoded messages.ListResponse[User]
+	if err := marshal.Unmarshal(resource, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.TotalResults != 1 || len(decoded.Resources) != 1 || decoded.Resources[0].UserName != "di-wu" {
+		t.Errorf("unexpected list: %+v", decoded)
+	}
+
+	var scimErr *messages.Error
+	if err := error(&Error{Status: http.StatusNotFound}); !errors.As(err, &scimErr) {
+		t.Errorf("expected a messages.Error, got %T", err)
+	}
+}
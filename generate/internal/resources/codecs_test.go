@@ -0,0 +1,48 @@
+package resources
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUser_UnmarshalSCIM(t *testing.T) {
+	var resource map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"userName": "di-wu",
+		"name": null,
+		"nickNames": ["quint", "di"]
+	}`), &resource); err != nil {
+		t.Fatal(err)
+	}
+
+	var user User
+	if err := user.UnmarshalSCIM(resource); err != nil {
+		t.Fatal(err)
+	}
+	if user.Name == nil || *user.Name != (UserName{}) {
+		t.Errorf("null name not decoded into an empty name: %v", user.Name)
+	}
+	if nickNames := []NickName{"quint", "di"}; !reflect.DeepEqual(user.NickNames, nickNames) {
+		t.Errorf("nick names do not match: %v, %v", user.NickNames, nickNames)
+	}
+
+	encoded, err := user.MarshalSCIM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := encoded["name"]; !ok || name != nil {
+		t.Errorf("empty name not encoded as null: %v", encoded)
+	}
+	var decoded User
+	if err := decoded.UnmarshalSCIM(encoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, user) {
+		t.Errorf("round trip does not match: %+v, %+v", decoded, user)
+	}
+
+	if err := user.UnmarshalSCIM(map[string]interface{}{"nickNames": []interface{}{1.5}}); err == nil {
+		t.Error("expected an error for a number as nick name")
+	}
+}
@@ -0,0 +1,5 @@
+// Package resources contains the resources generated from the schemas in cmd/scimgen/testdata. It is used to test the
+// generated code, e.g. the client.
+package resources
+
+//go:generate go run ../../cmd/scimgen -pkg resources -ptr -client -server -validate -tags scim,json -type nickNames=NickName -ext User=urn:ietf:params:scim:schemas:extension:enterprise:2.0:User ../../cmd/scimgen/testdata/user.json ../../cmd/scimgen/testdata/enterprise.json ../../cmd/scimgen/testdata/group.json
@@ -0,0 +1,243 @@
+// Code generated by github.com/scim2/tools/generate. DO NOT EDIT.
+
+package resources
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Group
+type Group struct {
+	DisplayName *string       `scim:"displayName" json:"displayName,omitempty"`
+	ExternalID  *string       `scim:"externalId" json:"externalId,omitempty"`
+	ID          string        `scim:"id" json:"id,omitempty"`
+	Members     []GroupMember `scim:"members,mV" json:"members,omitempty"`
+	Meta        *Meta         `scim:"meta" json:"meta,omitempty"`
+	Schemas     []string      `scim:"schemas,mV" json:"schemas,omitempty"`
+}
+
+// MarshalSCIM converts the Group into a SCIM resource.
+func (v Group) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.DisplayName != nil {
+		resource["displayName"] = *v.DisplayName
+	}
+	if v.ExternalID != nil {
+		resource["externalId"] = *v.ExternalID
+	}
+	if v.ID != "" {
+		resource["id"] = v.ID
+	}
+	if v.Members != nil {
+		values := make([]interface{}, 0, len(v.Members))
+		for _, value := range v.Members {
+			element, err := value.MarshalSCIM()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, element)
+		}
+		resource["members"] = values
+	}
+	if v.Meta != nil {
+		metaValue, err := v.Meta.MarshalSCIM()
+		if err != nil {
+			return nil, err
+		}
+		if len(metaValue) == 0 {
+			resource["meta"] = nil
+		} else {
+			resource["meta"] = metaValue
+		}
+	}
+	if v.Schemas != nil {
+		values := make([]interface{}, 0, len(v.Schemas))
+		for _, value := range v.Schemas {
+			values = append(values, value)
+		}
+		resource["schemas"] = values
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the Group with the given SCIM resource.
+func (v *Group) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["displayName"].(type) {
+	case nil:
+	case string:
+		displayNameValue := value
+		v.DisplayName = &displayNameValue
+	default:
+		return fmt.Errorf("types of \"displayName\" do not match: got %T, want string", value)
+	}
+	switch value := resource["externalId"].(type) {
+	case nil:
+	case string:
+		externalIDValue := value
+		v.ExternalID = &externalIDValue
+	default:
+		return fmt.Errorf("types of \"externalId\" do not match: got %T, want string", value)
+	}
+	switch value := resource["id"].(type) {
+	case nil:
+	case string:
+		v.ID = value
+	default:
+		return fmt.Errorf("types of \"id\" do not match: got %T, want string", value)
+	}
+	switch values := resource["members"].(type) {
+	case nil:
+	case []interface{}:
+		v.Members = make([]GroupMember, 0, len(values))
+		for _, value := range values {
+			switch value := value.(type) {
+			case map[string]interface{}:
+				var element GroupMember
+				if err := element.UnmarshalSCIM(value); err != nil {
+					return err
+				}
+				v.Members = append(v.Members, element)
+			default:
+				return fmt.Errorf("types of \"members\" do not match: got %T, want map[string]interface{}", value)
+			}
+		}
+	default:
+		return fmt.Errorf("types of \"members\" do not match: got %T, want []GroupMember", values)
+	}
+	switch value := resource["meta"].(type) {
+	case nil:
+		if _, ok := resource["meta"]; ok {
+			v.Meta = new(Meta)
+		}
+	case map[string]interface{}:
+		var element Meta
+		if err := element.UnmarshalSCIM(value); err != nil {
+			return err
+		}
+		metaValue := element
+		v.Meta = &metaValue
+	default:
+		return fmt.Errorf("types of \"meta\" do not match: got %T, want map[string]interface{}", value)
+	}
+	switch values := resource["schemas"].(type) {
+	case nil:
+	case []interface{}:
+		v.Schemas = make([]string, 0, len(values))
+		for _, value := range values {
+			switch value := value.(type) {
+			case string:
+				v.Schemas = append(v.Schemas, value)
+			default:
+				return fmt.Errorf("types of \"schemas\" do not match: got %T, want string", value)
+			}
+		}
+	default:
+		return fmt.Errorf("types of \"schemas\" do not match: got %T, want []string", values)
+	}
+	return nil
+}
+
+// Validate checks whether the Group conforms to the schema it was generated from.
+func (v Group) Validate() error {
+	for _, value := range v.Members {
+		if err := value.Validate(); err != nil {
+			return fmt.Errorf("members: %w", err)
+		}
+	}
+	if v.Meta != nil {
+		if err := v.Meta.Validate(); err != nil {
+			return fmt.Errorf("meta: %w", err)
+		}
+	}
+	if len(v.Schemas) == 0 {
+		return errors.New("required attribute \"schemas\" is missing")
+	}
+	return nil
+}
+
+// A list of members of the Group.
+type GroupMember struct {
+	Value *string          `scim:"value" json:"value,omitempty"`
+	Ref   *GroupMemberRef  `scim:"$ref" json:"$ref,omitempty"`
+	Type  *GroupMemberType `scim:"type" json:"type,omitempty"`
+}
+
+// MarshalSCIM converts the GroupMember into a SCIM resource.
+func (v GroupMember) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.Value != nil {
+		resource["value"] = *v.Value
+	}
+	if v.Ref != nil {
+		resource["$ref"] = string(*v.Ref)
+	}
+	if v.Type != nil {
+		resource["type"] = string(*v.Type)
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the GroupMember with the given SCIM resource.
+func (v *GroupMember) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["value"].(type) {
+	case nil:
+	case string:
+		valueValue := value
+		v.Value = &valueValue
+	default:
+		return fmt.Errorf("types of \"value\" do not match: got %T, want string", value)
+	}
+	switch value := resource["$ref"].(type) {
+	case nil:
+	case string:
+		refValue := GroupMemberRef(value)
+		v.Ref = &refValue
+	default:
+		return fmt.Errorf("types of \"$ref\" do not match: got %T, want string", value)
+	}
+	switch value := resource["type"].(type) {
+	case nil:
+	case string:
+		typeValue := GroupMemberType(value)
+		v.Type = &typeValue
+	default:
+		return fmt.Errorf("types of \"type\" do not match: got %T, want string", value)
+	}
+	return nil
+}
+
+// Validate checks whether the GroupMember conforms to the schema it was generated from.
+func (v GroupMember) Validate() error {
+	if v.Type != nil {
+		if !v.Type.IsValid() {
+			return fmt.Errorf("value of \"type\" is not a canonical value: %q", *v.Type)
+		}
+	}
+	return nil
+}
+
+// GroupMemberType represents the canonical values of the "type" attribute.
+type GroupMemberType string
+
+const (
+	GroupMemberTypeUser  GroupMemberType = "User"
+	GroupMemberTypeGroup GroupMemberType = "Group"
+)
+
+// IsValid checks whether the GroupMemberType is one of the canonical values.
+func (v GroupMemberType) IsValid() bool {
+	switch v {
+	case GroupMemberTypeUser, GroupMemberTypeGroup:
+		return true
+	}
+	return false
+}
+
+// GroupMemberRef represents a reference of the "$ref" attribute.
+type GroupMemberRef string
+
+// ReferenceTypes returns the types of the resources that a GroupMemberRef can reference.
+func (GroupMemberRef) ReferenceTypes() []string {
+	return []string{"User", "Group"}
+}
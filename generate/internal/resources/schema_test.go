@@ -0,0 +1,62 @@
+package resources
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/scim2/tools/schema"
+)
+
+// TestFromStruct checks whether the schemas derived from the generated structs match the schemas they were generated
+// from.
+func TestFromStruct(t *testing.T) {
+	for _, test := range []struct {
+		resource interface{}
+		file     string
+	}{
+		{User{}, "user.json"},
+		{EnterpriseUserExtension{}, "enterprise.json"},
+		{Group{}, "group.json"},
+	} {
+		raw, err := ioutil.ReadFile("../../cmd/scimgen/testdata/" + test.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var expected schema.ReferenceSchema
+		if err := json.Unmarshal(raw, &expected); err != nil {
+			t.Fatal(err)
+		}
+		actual, err := schema.FromStruct(test.resource)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compareAttributes(t, test.file, expected.Attributes, actual.Attributes)
+	}
+}
+
+// compareAttributes compares the attributes by name, the generated fields are sorted.
+func compareAttributes(t *testing.T, path string, expected, actual []*schema.Attribute) {
+	if len(expected) != len(actual) {
+		t.Errorf("%s: expected %d attributes, got %d", path, len(expected), len(actual))
+		return
+	}
+	attrs := make(map[string]*schema.Attribute, len(actual))
+	for _, a := range actual {
+		attrs[a.Name] = a
+	}
+	for _, e := range expected {
+		a, ok := attrs[e.Name]
+		if !ok {
+			t.Errorf("%s: attribute %s not found", path, e.Name)
+			continue
+		}
+		if e.Type != a.Type || e.MultiValued != a.MultiValued {
+			t.Errorf("%s.%s: expected %s (mV: %v), got %s (mV: %v)", path, e.Name, e.Type, e.MultiValued, a.Type, a.MultiValued)
+		}
+		if len(e.ReferenceTypes) != len(a.ReferenceTypes) {
+			t.Errorf("%s.%s: expected reference types %v, got %v", path, e.Name, e.ReferenceTypes, a.ReferenceTypes)
+		}
+		compareAttributes(t, path+"."+e.Name, e.SubAttributes, a.SubAttributes)
+	}
+}
@@ -0,0 +1,231 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// userStore stores the users in memory.
+type userStore struct {
+	users   map[string]User
+	queries []Query
+}
+
+func (s *userStore) Get(_ context.Context, id string) (*User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return nil, &Error{Status: http.StatusNotFound, Detail: fmt.Sprintf("user %q not found", id)}
+	}
+	return &user, nil
+}
+
+func (s *userStore) List(_ context.Context, query Query) ([]User, int, error) {
+	s.queries = append(s.queries, query)
+	if query.Filter == "invalid" {
+		return nil, 0, &Error{Status: http.StatusBadRequest, SCIMType: "invalidFilter", Detail: "invalid filter"}
+	}
+	if query.Filter == "unavailable" {
+		return nil, 0, &Error{Detail: "store unavailable"}
+	}
+	var users []User
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, len(users), nil
+}
+
+func (s *userStore) Create(_ context.Context, resource User) (*User, error) {
+	resource.ID = fmt.Sprintf("%04d", len(s.users)+1)
+	location := MetaLocation("https://example.com/Users/" + resource.ID)
+	resource.Meta = &Meta{Location: &location}
+	s.users[resource.ID] = resource
+	return &resource, nil
+}
+
+func (s *userStore) Replace(_ context.Context, id string, resource User) (*User, error) {
+	if _, ok := s.users[id]; !ok {
+		return nil, &Error{Status: http.StatusNotFound, Detail: fmt.Sprintf("user %q not found", id)}
+	}
+	resource.ID = id
+	s.users[id] = resource
+	return &resource, nil
+}
+
+func (s *userStore) Patch(_ context.Context, id string, ops []PatchOperation) (*User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return nil, &Error{Status: http.StatusNotFound, Detail: fmt.Sprintf("user %q not found", id)}
+	}
+	for _, op := range ops {
+		if op.Op != "replace" || op.Path != "displayName" {
+			return nil, errors.New("unsupported operation")
+		}
+		displayName, _ := op.Value.(string)
+		user.DisplayName = &displayName
+	}
+	s.users[id] = user
+	return nil, nil
+}
+
+func (s *userStore) Delete(_ context.Context, id string) error {
+	if _, ok := s.users[id]; !ok {
+		return &Error{Status: http.StatusNotFound, Detail: fmt.Sprintf("user %q not found", id)}
+	}
+	delete(s.users, id)
+	return nil
+}
+
+func TestUserHandler(t *testing.T) {
+	store := &userStore{users: make(map[string]User)}
+	server := httptest.NewServer(NewUserHandler(store))
+	defer server.Close()
+
+	ctx := context.Background()
+	users := NewClient(server.URL, server.Client()).Users()
+
+	employeeNumber := "1"
+	created, err := users.Create(ctx, User{
+		UserName: "di-wu",
+		EnterpriseUser: EnterpriseUserExtension{
+			EmployeeNumber: &employeeNumber,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "0001" || *created.EnterpriseUser.EmployeeNumber != "1" {
+		t.Errorf("unexpected user: %+v", created)
+	}
+	if len(created.Schemas) != 2 {
+		t.Errorf("unexpected schemas: %v", created.Schemas)
+	}
+
+	if _, err := users.Create(ctx, User{UserName: "quint"}); err != nil {
+		t.Fatal(err)
+	}
+
+	count := 10
+	list, err := users.List(ctx, Query{Filter: `userName sw "q"`, StartIndex: 2, Count: &count})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.TotalResults != 2 || list.ItemsPerPage != 2 || list.StartIndex != 2 || len(list.Resources) != 2 {
+		t.Errorf("unexpected list: %+v", list)
+	}
+	if q := store.queries[0]; q.Filter != `userName sw "q"` || q.StartIndex != 2 || q.Count == nil || *q.Count != 10 {
+		t.Errorf("unexpected query: %+v", q)
+	}
+
+	displayName := "Quint"
+	if patched, err := users.Patch(ctx, "0002", []PatchOperation{{Op: "replace", Path: "displayName", Value: displayName}}); err != nil || patched != nil {
+		t.Errorf("unexpected patch result: %v, %v", patched, err)
+	}
+	user, err := users.Get(ctx, "0002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.DisplayName == nil || *user.DisplayName != displayName {
+		t.Errorf("unexpected user: %+v", user)
+	}
+
+	user.UserName = "quint-v2"
+	if replaced, err := users.Replace(ctx, "0002", *user); err != nil || replaced.UserName != "quint-v2" {
+		t.Errorf("unexpected replace result: %v, %v", replaced, err)
+	}
+
+	if err := users.Delete(ctx, "0001"); err != nil {
+		t.Fatal(err)
+	}
+	var scimErr *Error
+	if _, err := users.Get(ctx, "0001"); !errors.As(err, &scimErr) || scimErr.Status != http.StatusNotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if _, err := users.List(ctx, Query{Filter: "invalid"}); !errors.As(err, &scimErr) || scimErr.SCIMType != "invalidFilter" {
+		t.Errorf("expected invalid filter error, got %v", err)
+	}
+	if _, err := users.Create(ctx, User{}); !errors.As(err, &scimErr) || scimErr.SCIMType != "invalidValue" {
+		t.Errorf("expected invalid value error, got %v", err)
+	}
+	if _, err := users.Patch(ctx, "0002", []PatchOperation{{Op: "add", Path: "nickName", Value: "q"}}); !errors.As(err, &scimErr) || scimErr.Status != http.StatusInternalServerError || scimErr.Detail == "unsupported operation" {
+		t.Errorf("expected internal server error, got %v", err)
+	}
+}
+
+func TestUserHandler_requests(t *testing.T) {
+	store := &userStore{users: map[string]User{"0001": {ID: "0001", UserName: "di-wu"}}}
+	handler := NewUserHandler(store)
+
+	for _, test := range []struct {
+		method, target, body string
+		status               int
+		scimType             string
+	}{
+		{method: http.MethodPost, target: "/Users/.search", body: `{"filter": "userName eq \"di-wu\"", "startIndex": 0, "count": -1, "attributes": ["userName"]}`, status: http.StatusOK},
+		{method: http.MethodGet, target: "/Users?attributes=userName,%20name&excludedAttributes=emails&sortBy=userName&sortOrder=descending", status: http.StatusOK},
+		{method: http.MethodGet, target: "/Users/0001", status: http.StatusOK},
+		{method: http.MethodPost, target: "/Users", body: `{"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"], "userName": "quint"}`, status: http.StatusCreated},
+		{method: http.MethodGet, target: "/Users?filter=unavailable", status: http.StatusInternalServerError},
+		{method: http.MethodGet, target: "/Users?count=ten", status: http.StatusBadRequest, scimType: "invalidValue"},
+		{method: http.MethodGet, target: "/Users?sortOrder=up", status: http.StatusBadRequest, scimType: "invalidValue"},
+		{method: http.MethodPost, target: "/Users/.search", body: `{"attributes": [1]}`, status: http.StatusBadRequest, scimType: "invalidValue"},
+		{method: http.MethodPost, target: "/Users/.search", body: `{"startIndex": 1.5}`, status: http.StatusBadRequest, scimType: "invalidValue"},
+		{method: http.MethodPost, target: "/Users", body: `{`, status: http.StatusBadRequest, scimType: "invalidSyntax"},
+		{method: http.MethodPost, target: "/Users", body: `{"userName": 1}`, status: http.StatusBadRequest, scimType: "invalidValue"},
+		{method: http.MethodPatch, target: "/Users/0001", body: `{"Operations": []}`, status: http.StatusBadRequest, scimType: "invalidSyntax"},
+		{method: http.MethodPatch, target: "/Users/0001", body: `{"Operations": [{"op": "move"}]}`, status: http.StatusBadRequest, scimType: "invalidSyntax"},
+		{method: http.MethodPatch, target: "/Users/0001", body: `{"Operations": [{"op": "add", "path": "nickName", "value": null}]}`, status: http.StatusBadRequest, scimType: "invalidSyntax"},
+		{method: http.MethodPatch, target: "/Users/0001", body: `{"Operations": [{"op": "Remove"}]}`, status: http.StatusBadRequest, scimType: "noTarget"},
+		{method: http.MethodPatch, target: "/Users/0001", body: `{"schemas": ["urn:ietf:params:scim:api:messages:2.0:SearchRequest"], "Operations": [{"op": "remove", "path": "title"}]}`, status: http.StatusBadRequest, scimType: "invalidSyntax"},
+		{method: http.MethodDelete, target: "/Users", status: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, target: "/Users/.search", status: http.StatusMethodNotAllowed},
+		{method: http.MethodPost, target: "/Users/0001", body: `{}`, status: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, target: "/Users/0001/name", status: http.StatusNotFound},
+		{method: http.MethodGet, target: "/Groups", status: http.StatusNotFound},
+	} {
+		t.Run(test.method+" "+test.target, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != test.status {
+				t.Errorf("expected status %d, got %d: %s", test.status, rec.Code, rec.Body)
+			}
+			if test.status == http.StatusCreated && rec.Header().Get("Location") != "https://example.com/Users/0002" {
+				t.Errorf("unexpected location: %q", rec.Header().Get("Location"))
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/scim+json" {
+				t.Errorf("unexpected content type: %q", ct)
+			}
+			var body map[string]interface{}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if test.status < 300 {
+				return
+			}
+			if body["status"] != fmt.Sprint(test.status) || body["scimType"] != nil && body["scimType"] != test.scimType || test.scimType != "" && body["scimType"] == nil {
+				t.Errorf("unexpected error: %v", body)
+			}
+			schemas, _ := body["schemas"].([]interface{})
+			if len(schemas) != 1 || schemas[0] != "urn:ietf:params:scim:api:messages:2.0:Error" {
+				t.Errorf("unexpected schemas: %v", schemas)
+			}
+		})
+	}
+
+	search, list := store.queries[0], store.queries[1]
+	if search.Filter != `userName eq "di-wu"` || search.StartIndex != 1 || search.Count == nil || *search.Count != 0 || len(search.Attributes) != 1 {
+		t.Errorf("unexpected search query: %+v", search)
+	}
+	if list.StartIndex != 1 || list.Count != nil || len(list.Attributes) != 2 || list.Attributes[1] != "name" || list.ExcludedAttributes[0] != "emails" || list.SortOrder != "descending" {
+		t.Errorf("unexpected list query: %+v", list)
+	}
+}
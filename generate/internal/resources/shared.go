@@ -0,0 +1,110 @@
+// Code generated by github.com/scim2/tools/generate. DO NOT EDIT.
+
+package resources
+
+import (
+	"fmt"
+	"time"
+)
+
+// A complex attribute containing resource metadata.
+type Meta struct {
+	ResourceType *string       `scim:"resourceType" json:"resourceType,omitempty"`
+	Created      *time.Time    `scim:"created" json:"created,omitempty"`
+	LastModified *time.Time    `scim:"lastModified" json:"lastModified,omitempty"`
+	Location     *MetaLocation `scim:"location" json:"location,omitempty"`
+	Version      *string       `scim:"version" json:"version,omitempty"`
+}
+
+// MarshalSCIM converts the Meta into a SCIM resource.
+func (v Meta) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.ResourceType != nil {
+		resource["resourceType"] = *v.ResourceType
+	}
+	if v.Created != nil {
+		resource["created"] = v.Created.Format(time.RFC3339)
+	}
+	if v.LastModified != nil {
+		resource["lastModified"] = v.LastModified.Format(time.RFC3339)
+	}
+	if v.Location != nil {
+		resource["location"] = string(*v.Location)
+	}
+	if v.Version != nil {
+		resource["version"] = *v.Version
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the Meta with the given SCIM resource.
+func (v *Meta) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["resourceType"].(type) {
+	case nil:
+	case string:
+		resourceTypeValue := value
+		v.ResourceType = &resourceTypeValue
+	default:
+		return fmt.Errorf("types of \"resourceType\" do not match: got %T, want string", value)
+	}
+	switch value := resource["created"].(type) {
+	case nil:
+	case string:
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("value of \"created\" is not a valid dateTime: %v", err)
+		}
+		createdValue := parsed
+		v.Created = &createdValue
+	case time.Time:
+		createdValue := value
+		v.Created = &createdValue
+	default:
+		return fmt.Errorf("types of \"created\" do not match: got %T, want string", value)
+	}
+	switch value := resource["lastModified"].(type) {
+	case nil:
+	case string:
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("value of \"lastModified\" is not a valid dateTime: %v", err)
+		}
+		lastModifiedValue := parsed
+		v.LastModified = &lastModifiedValue
+	case time.Time:
+		lastModifiedValue := value
+		v.LastModified = &lastModifiedValue
+	default:
+		return fmt.Errorf("types of \"lastModified\" do not match: got %T, want string", value)
+	}
+	switch value := resource["location"].(type) {
+	case nil:
+	case string:
+		locationValue := MetaLocation(value)
+		v.Location = &locationValue
+	default:
+		return fmt.Errorf("types of \"location\" do not match: got %T, want string", value)
+	}
+	switch value := resource["version"].(type) {
+	case nil:
+	case string:
+		versionValue := value
+		v.Version = &versionValue
+	default:
+		return fmt.Errorf("types of \"version\" do not match: got %T, want string", value)
+	}
+	return nil
+}
+
+// Validate checks whether the Meta conforms to the schema it was generated from.
+func (v Meta) Validate() error {
+	return nil
+}
+
+// MetaLocation represents a reference of the "location" attribute.
+type MetaLocation string
+
+// ReferenceTypes returns the types of the resources that a MetaLocation can reference.
+func (MetaLocation) ReferenceTypes() []string {
+	return []string{"uri"}
+}
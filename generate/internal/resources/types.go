@@ -0,0 +1,4 @@
+package resources
+
+// NickName is the custom type of the nick names of a user, it is used to test the codecs of custom types.
+type NickName string
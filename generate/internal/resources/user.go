@@ -0,0 +1,938 @@
+// Code generated by github.com/scim2/tools/generate. DO NOT EDIT.
+
+package resources
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// User Account
+type User struct {
+	Active           *bool                 `scim:"active" json:"active,omitempty"`
+	DisplayName      *string               `scim:"displayName" json:"displayName,omitempty"`
+	Emails           []UserEmail           `scim:"emails,mV" json:"emails,omitempty"`
+	ExternalID       *string               `scim:"externalId" json:"externalId,omitempty"`
+	Groups           []UserGroup           `scim:"groups,mV" json:"groups,omitempty"`
+	ID               string                `scim:"id" json:"id,omitempty"`
+	LastLogin        *time.Time            `scim:"lastLogin" json:"lastLogin,omitempty"`
+	LoginCount       *int                  `scim:"loginCount" json:"loginCount,omitempty"`
+	Meta             *Meta                 `scim:"meta" json:"meta,omitempty"`
+	Name             *UserName             `scim:"name" json:"name,omitempty"`
+	NickNames        []NickName            `scim:"nickNames,mV" json:"nickNames,omitempty"`
+	Password         *string               `scim:"password" json:"password,omitempty"`
+	PhoneNumbers     []UserPhoneNumber     `scim:"phoneNumbers,mV" json:"phoneNumbers,omitempty"`
+	ProfileUrl       *UserProfileUrl       `scim:"profileUrl" json:"profileUrl,omitempty"`
+	Schemas          []string              `scim:"schemas,mV" json:"schemas,omitempty"`
+	Score            *float64              `scim:"score" json:"score,omitempty"`
+	UserName         string                `scim:"userName" json:"userName,omitempty"`
+	X509Certificates []UserX509Certificate `scim:"x509Certificates,mV" json:"x509Certificates,omitempty"`
+
+	EnterpriseUser EnterpriseUserExtension `scim:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,inline" json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,omitempty"`
+}
+
+// MarshalSCIM converts the User into a SCIM resource.
+func (v User) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.Active != nil {
+		resource["active"] = *v.Active
+	}
+	if v.DisplayName != nil {
+		resource["displayName"] = *v.DisplayName
+	}
+	if v.Emails != nil {
+		values := make([]interface{}, 0, len(v.Emails))
+		for _, value := range v.Emails {
+			element, err := value.MarshalSCIM()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, element)
+		}
+		resource["emails"] = values
+	}
+	if v.ExternalID != nil {
+		resource["externalId"] = *v.ExternalID
+	}
+	if v.Groups != nil {
+		values := make([]interface{}, 0, len(v.Groups))
+		for _, value := range v.Groups {
+			element, err := value.MarshalSCIM()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, element)
+		}
+		resource["groups"] = values
+	}
+	if v.ID != "" {
+		resource["id"] = v.ID
+	}
+	if v.LastLogin != nil {
+		resource["lastLogin"] = v.LastLogin.Format(time.RFC3339)
+	}
+	if v.LoginCount != nil {
+		resource["loginCount"] = *v.LoginCount
+	}
+	if v.Meta != nil {
+		metaValue, err := v.Meta.MarshalSCIM()
+		if err != nil {
+			return nil, err
+		}
+		if len(metaValue) == 0 {
+			resource["meta"] = nil
+		} else {
+			resource["meta"] = metaValue
+		}
+	}
+	if v.Name != nil {
+		nameValue, err := v.Name.MarshalSCIM()
+		if err != nil {
+			return nil, err
+		}
+		if len(nameValue) == 0 {
+			resource["name"] = nil
+		} else {
+			resource["name"] = nameValue
+		}
+	}
+	if v.NickNames != nil {
+		values := make([]interface{}, 0, len(v.NickNames))
+		for _, value := range v.NickNames {
+			values = append(values, value)
+		}
+		resource["nickNames"] = values
+	}
+	if v.Password != nil {
+		resource["password"] = *v.Password
+	}
+	if v.PhoneNumbers != nil {
+		values := make([]interface{}, 0, len(v.PhoneNumbers))
+		for _, value := range v.PhoneNumbers {
+			element, err := value.MarshalSCIM()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, element)
+		}
+		resource["phoneNumbers"] = values
+	}
+	if v.ProfileUrl != nil {
+		resource["profileUrl"] = string(*v.ProfileUrl)
+	}
+	if v.Schemas != nil {
+		values := make([]interface{}, 0, len(v.Schemas))
+		for _, value := range v.Schemas {
+			values = append(values, value)
+		}
+		resource["schemas"] = values
+	}
+	if v.Score != nil {
+		resource["score"] = *v.Score
+	}
+	if v.UserName != "" {
+		resource["userName"] = v.UserName
+	}
+	if v.X509Certificates != nil {
+		values := make([]interface{}, 0, len(v.X509Certificates))
+		for _, value := range v.X509Certificates {
+			element, err := value.MarshalSCIM()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, element)
+		}
+		resource["x509Certificates"] = values
+	}
+	enterpriseUserValue, err := v.EnterpriseUser.MarshalSCIM()
+	if err != nil {
+		return nil, err
+	}
+	if len(enterpriseUserValue) != 0 {
+		resource["urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"] = enterpriseUserValue
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the User with the given SCIM resource.
+func (v *User) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["active"].(type) {
+	case nil:
+	case bool:
+		activeValue := value
+		v.Active = &activeValue
+	default:
+		return fmt.Errorf("types of \"active\" do not match: got %T, want bool", value)
+	}
+	switch value := resource["displayName"].(type) {
+	case nil:
+	case string:
+		displayNameValue := value
+		v.DisplayName = &displayNameValue
+	default:
+		return fmt.Errorf("types of \"displayName\" do not match: got %T, want string", value)
+	}
+	switch values := resource["emails"].(type) {
+	case nil:
+	case []interface{}:
+		v.Emails = make([]UserEmail, 0, len(values))
+		for _, value := range values {
+			switch value := value.(type) {
+			case map[string]interface{}:
+				var element UserEmail
+				if err := element.UnmarshalSCIM(value); err != nil {
+					return err
+				}
+				v.Emails = append(v.Emails, element)
+			default:
+				return fmt.Errorf("types of \"emails\" do not match: got %T, want map[string]interface{}", value)
+			}
+		}
+	default:
+		return fmt.Errorf("types of \"emails\" do not match: got %T, want []UserEmail", values)
+	}
+	switch value := resource["externalId"].(type) {
+	case nil:
+	case string:
+		externalIDValue := value
+		v.ExternalID = &externalIDValue
+	default:
+		return fmt.Errorf("types of \"externalId\" do not match: got %T, want string", value)
+	}
+	switch values := resource["groups"].(type) {
+	case nil:
+	case []interface{}:
+		v.Groups = make([]UserGroup, 0, len(values))
+		for _, value := range values {
+			switch value := value.(type) {
+			case map[string]interface{}:
+				var element UserGroup
+				if err := element.UnmarshalSCIM(value); err != nil {
+					return err
+				}
+				v.Groups = append(v.Groups, element)
+			default:
+				return fmt.Errorf("types of \"groups\" do not match: got %T, want map[string]interface{}", value)
+			}
+		}
+	default:
+		return fmt.Errorf("types of \"groups\" do not match: got %T, want []UserGroup", values)
+	}
+	switch value := resource["id"].(type) {
+	case nil:
+	case string:
+		v.ID = value
+	default:
+		return fmt.Errorf("types of \"id\" do not match: got %T, want string", value)
+	}
+	switch value := resource["lastLogin"].(type) {
+	case nil:
+	case string:
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("value of \"lastLogin\" is not a valid dateTime: %v", err)
+		}
+		lastLoginValue := parsed
+		v.LastLogin = &lastLoginValue
+	case time.Time:
+		lastLoginValue := value
+		v.LastLogin = &lastLoginValue
+	default:
+		return fmt.Errorf("types of \"lastLogin\" do not match: got %T, want string", value)
+	}
+	switch value := resource["loginCount"].(type) {
+	case nil:
+	case int:
+		loginCountValue := value
+		v.LoginCount = &loginCountValue
+	case int64:
+		loginCountValue := int(value)
+		v.LoginCount = &loginCountValue
+	case float64:
+		if value != float64(int(value)) {
+			return fmt.Errorf("value of \"loginCount\" is not a valid int: %v", value)
+		}
+		loginCountValue := int(value)
+		v.LoginCount = &loginCountValue
+	default:
+		return fmt.Errorf("types of \"loginCount\" do not match: got %T, want int", value)
+	}
+	switch value := resource["meta"].(type) {
+	case nil:
+		if _, ok := resource["meta"]; ok {
+			v.Meta = new(Meta)
+		}
+	case map[string]interface{}:
+		var element Meta
+		if err := element.UnmarshalSCIM(value); err != nil {
+			return err
+		}
+		metaValue := element
+		v.Meta = &metaValue
+	default:
+		return fmt.Errorf("types of \"meta\" do not match: got %T, want map[string]interface{}", value)
+	}
+	switch value := resource["name"].(type) {
+	case nil:
+		if _, ok := resource["name"]; ok {
+			v.Name = new(UserName)
+		}
+	case map[string]interface{}:
+		var element UserName
+		if err := element.UnmarshalSCIM(value); err != nil {
+			return err
+		}
+		nameValue := element
+		v.Name = &nameValue
+	default:
+		return fmt.Errorf("types of \"name\" do not match: got %T, want map[string]interface{}", value)
+	}
+	switch values := resource["nickNames"].(type) {
+	case nil:
+	case []interface{}:
+		v.NickNames = make([]NickName, 0, len(values))
+		for _, value := range values {
+			switch value := value.(type) {
+			case NickName:
+				v.NickNames = append(v.NickNames, value)
+			case string, float64, bool:
+				raw, _ := json.Marshal(value)
+				var element NickName
+				if err := json.Unmarshal(raw, &element); err != nil {
+					return fmt.Errorf("value of \"nickNames\" is not a valid NickName: %v", err)
+				}
+				v.NickNames = append(v.NickNames, element)
+			default:
+				return fmt.Errorf("types of \"nickNames\" do not match: got %T, want NickName", value)
+			}
+		}
+	default:
+		return fmt.Errorf("types of \"nickNames\" do not match: got %T, want []NickName", values)
+	}
+	switch value := resource["password"].(type) {
+	case nil:
+	case string:
+		passwordValue := value
+		v.Password = &passwordValue
+	default:
+		return fmt.Errorf("types of \"password\" do not match: got %T, want string", value)
+	}
+	switch values := resource["phoneNumbers"].(type) {
+	case nil:
+	case []interface{}:
+		v.PhoneNumbers = make([]UserPhoneNumber, 0, len(values))
+		for _, value := range values {
+			switch value := value.(type) {
+			case map[string]interface{}:
+				var element UserPhoneNumber
+				if err := element.UnmarshalSCIM(value); err != nil {
+					return err
+				}
+				v.PhoneNumbers = append(v.PhoneNumbers, element)
+			default:
+				return fmt.Errorf("types of \"phoneNumbers\" do not match: got %T, want map[string]interface{}", value)
+			}
+		}
+	default:
+		return fmt.Errorf("types of \"phoneNumbers\" do not match: got %T, want []UserPhoneNumber", values)
+	}
+	switch value := resource["profileUrl"].(type) {
+	case nil:
+	case string:
+		profileUrlValue := UserProfileUrl(value)
+		v.ProfileUrl = &profileUrlValue
+	default:
+		return fmt.Errorf("types of \"profileUrl\" do not match: got %T, want string", value)
+	}
+	switch values := resource["schemas"].(type) {
+	case nil:
+	case []interface{}:
+		v.Schemas = make([]string, 0, len(values))
+		for _, value := range values {
+			switch value := value.(type) {
+			case string:
+				v.Schemas = append(v.Schemas, value)
+			default:
+				return fmt.Errorf("types of \"schemas\" do not match: got %T, want string", value)
+			}
+		}
+	default:
+		return fmt.Errorf("types of \"schemas\" do not match: got %T, want []string", values)
+	}
+	switch value := resource["score"].(type) {
+	case nil:
+	case float64:
+		scoreValue := value
+		v.Score = &scoreValue
+	case int:
+		scoreValue := float64(value)
+		v.Score = &scoreValue
+	case int64:
+		scoreValue := float64(value)
+		v.Score = &scoreValue
+	default:
+		return fmt.Errorf("types of \"score\" do not match: got %T, want float64", value)
+	}
+	switch value := resource["userName"].(type) {
+	case nil:
+	case string:
+		v.UserName = value
+	default:
+		return fmt.Errorf("types of \"userName\" do not match: got %T, want string", value)
+	}
+	switch values := resource["x509Certificates"].(type) {
+	case nil:
+	case []interface{}:
+		v.X509Certificates = make([]UserX509Certificate, 0, len(values))
+		for _, value := range values {
+			switch value := value.(type) {
+			case map[string]interface{}:
+				var element UserX509Certificate
+				if err := element.UnmarshalSCIM(value); err != nil {
+					return err
+				}
+				v.X509Certificates = append(v.X509Certificates, element)
+			default:
+				return fmt.Errorf("types of \"x509Certificates\" do not match: got %T, want map[string]interface{}", value)
+			}
+		}
+	default:
+		return fmt.Errorf("types of \"x509Certificates\" do not match: got %T, want []UserX509Certificate", values)
+	}
+	switch value := resource["urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"].(type) {
+	case nil:
+	case map[string]interface{}:
+		var element EnterpriseUserExtension
+		if err := element.UnmarshalSCIM(value); err != nil {
+			return err
+		}
+		v.EnterpriseUser = element
+	default:
+		return fmt.Errorf("types of \"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User\" do not match: got %T, want map[string]interface{}", value)
+	}
+	return nil
+}
+
+// Validate checks whether the User conforms to the schema it was generated from.
+func (v User) Validate() error {
+	var emailsPrimary bool
+	for _, value := range v.Emails {
+		if err := value.Validate(); err != nil {
+			return fmt.Errorf("emails: %w", err)
+		}
+		if value.Primary != nil && *value.Primary {
+			if emailsPrimary {
+				return errors.New("multiple primary values of \"emails\"")
+			}
+			emailsPrimary = true
+		}
+	}
+	for _, value := range v.Groups {
+		if err := value.Validate(); err != nil {
+			return fmt.Errorf("groups: %w", err)
+		}
+	}
+	if v.Meta != nil {
+		if err := v.Meta.Validate(); err != nil {
+			return fmt.Errorf("meta: %w", err)
+		}
+	}
+	if v.Name != nil {
+		if err := v.Name.Validate(); err != nil {
+			return fmt.Errorf("name: %w", err)
+		}
+	}
+	var phoneNumbersPrimary bool
+	for _, value := range v.PhoneNumbers {
+		if err := value.Validate(); err != nil {
+			return fmt.Errorf("phoneNumbers: %w", err)
+		}
+		if value.Primary != nil && *value.Primary {
+			if phoneNumbersPrimary {
+				return errors.New("multiple primary values of \"phoneNumbers\"")
+			}
+			phoneNumbersPrimary = true
+		}
+	}
+	if len(v.Schemas) == 0 {
+		return errors.New("required attribute \"schemas\" is missing")
+	}
+	if v.UserName == "" {
+		return errors.New("required attribute \"userName\" is missing")
+	}
+	for _, value := range v.X509Certificates {
+		if err := value.Validate(); err != nil {
+			return fmt.Errorf("x509Certificates: %w", err)
+		}
+	}
+	if v.EnterpriseUser != (EnterpriseUserExtension{}) {
+		if err := v.EnterpriseUser.Validate(); err != nil {
+			return fmt.Errorf("urn:ietf:params:scim:schemas:extension:enterprise:2.0:User: %w", err)
+		}
+	}
+	return nil
+}
+
+// UserProfileUrl represents a reference of the "profileUrl" attribute.
+type UserProfileUrl string
+
+// ReferenceTypes returns the types of the resources that a UserProfileUrl can reference.
+func (UserProfileUrl) ReferenceTypes() []string {
+	return []string{"external"}
+}
+
+type UserEmail struct {
+	Value   *string        `scim:"value" json:"value,omitempty"`
+	Display *string        `scim:"display" json:"display,omitempty"`
+	Type    *UserEmailType `scim:"type" json:"type,omitempty"`
+	Primary *bool          `scim:"primary" json:"primary,omitempty"`
+}
+
+// MarshalSCIM converts the UserEmail into a SCIM resource.
+func (v UserEmail) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.Value != nil {
+		resource["value"] = *v.Value
+	}
+	if v.Display != nil {
+		resource["display"] = *v.Display
+	}
+	if v.Type != nil {
+		resource["type"] = string(*v.Type)
+	}
+	if v.Primary != nil {
+		resource["primary"] = *v.Primary
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the UserEmail with the given SCIM resource.
+func (v *UserEmail) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["value"].(type) {
+	case nil:
+	case string:
+		valueValue := value
+		v.Value = &valueValue
+	default:
+		return fmt.Errorf("types of \"value\" do not match: got %T, want string", value)
+	}
+	switch value := resource["display"].(type) {
+	case nil:
+	case string:
+		displayValue := value
+		v.Display = &displayValue
+	default:
+		return fmt.Errorf("types of \"display\" do not match: got %T, want string", value)
+	}
+	switch value := resource["type"].(type) {
+	case nil:
+	case string:
+		typeValue := UserEmailType(value)
+		v.Type = &typeValue
+	default:
+		return fmt.Errorf("types of \"type\" do not match: got %T, want string", value)
+	}
+	switch value := resource["primary"].(type) {
+	case nil:
+	case bool:
+		primaryValue := value
+		v.Primary = &primaryValue
+	default:
+		return fmt.Errorf("types of \"primary\" do not match: got %T, want bool", value)
+	}
+	return nil
+}
+
+// Validate checks whether the UserEmail conforms to the schema it was generated from.
+func (v UserEmail) Validate() error {
+	if v.Type != nil {
+		if !v.Type.IsValid() {
+			return fmt.Errorf("value of \"type\" is not a canonical value: %q", *v.Type)
+		}
+	}
+	return nil
+}
+
+// UserEmailType represents the canonical values of the "type" attribute.
+type UserEmailType string
+
+const (
+	UserEmailTypeWork  UserEmailType = "work"
+	UserEmailTypeHome  UserEmailType = "home"
+	UserEmailTypeOther UserEmailType = "other"
+)
+
+// IsValid checks whether the UserEmailType is one of the canonical values.
+func (v UserEmailType) IsValid() bool {
+	switch v {
+	case UserEmailTypeWork, UserEmailTypeHome, UserEmailTypeOther:
+		return true
+	}
+	return false
+}
+
+type UserGroup struct {
+	Value   *string       `scim:"value" json:"value,omitempty"`
+	Ref     *UserGroupRef `scim:"$ref" json:"$ref,omitempty"`
+	Display *string       `scim:"display" json:"display,omitempty"`
+}
+
+// MarshalSCIM converts the UserGroup into a SCIM resource.
+func (v UserGroup) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.Value != nil {
+		resource["value"] = *v.Value
+	}
+	if v.Ref != nil {
+		resource["$ref"] = string(*v.Ref)
+	}
+	if v.Display != nil {
+		resource["display"] = *v.Display
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the UserGroup with the given SCIM resource.
+func (v *UserGroup) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["value"].(type) {
+	case nil:
+	case string:
+		valueValue := value
+		v.Value = &valueValue
+	default:
+		return fmt.Errorf("types of \"value\" do not match: got %T, want string", value)
+	}
+	switch value := resource["$ref"].(type) {
+	case nil:
+	case string:
+		refValue := UserGroupRef(value)
+		v.Ref = &refValue
+	default:
+		return fmt.Errorf("types of \"$ref\" do not match: got %T, want string", value)
+	}
+	switch value := resource["display"].(type) {
+	case nil:
+	case string:
+		displayValue := value
+		v.Display = &displayValue
+	default:
+		return fmt.Errorf("types of \"display\" do not match: got %T, want string", value)
+	}
+	return nil
+}
+
+// Validate checks whether the UserGroup conforms to the schema it was generated from.
+func (v UserGroup) Validate() error {
+	return nil
+}
+
+// UserGroupRef represents a reference of the "$ref" attribute.
+type UserGroupRef string
+
+// ReferenceTypes returns the types of the resources that a UserGroupRef can reference.
+func (UserGroupRef) ReferenceTypes() []string {
+	return []string{"User", "Group"}
+}
+
+// The components of the user's real name.
+type UserName struct {
+	Formatted  *string `scim:"formatted" json:"formatted,omitempty"`
+	FamilyName *string `scim:"familyName" json:"familyName,omitempty"`
+	GivenName  *string `scim:"givenName" json:"givenName,omitempty"`
+}
+
+// MarshalSCIM converts the UserName into a SCIM resource.
+func (v UserName) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.Formatted != nil {
+		resource["formatted"] = *v.Formatted
+	}
+	if v.FamilyName != nil {
+		resource["familyName"] = *v.FamilyName
+	}
+	if v.GivenName != nil {
+		resource["givenName"] = *v.GivenName
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the UserName with the given SCIM resource.
+func (v *UserName) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["formatted"].(type) {
+	case nil:
+	case string:
+		formattedValue := value
+		v.Formatted = &formattedValue
+	default:
+		return fmt.Errorf("types of \"formatted\" do not match: got %T, want string", value)
+	}
+	switch value := resource["familyName"].(type) {
+	case nil:
+	case string:
+		familyNameValue := value
+		v.FamilyName = &familyNameValue
+	default:
+		return fmt.Errorf("types of \"familyName\" do not match: got %T, want string", value)
+	}
+	switch value := resource["givenName"].(type) {
+	case nil:
+	case string:
+		givenNameValue := value
+		v.GivenName = &givenNameValue
+	default:
+		return fmt.Errorf("types of \"givenName\" do not match: got %T, want string", value)
+	}
+	return nil
+}
+
+// Validate checks whether the UserName conforms to the schema it was generated from.
+func (v UserName) Validate() error {
+	return nil
+}
+
+type UserPhoneNumber struct {
+	Value   *string              `scim:"value" json:"value,omitempty"`
+	Type    *UserPhoneNumberType `scim:"type" json:"type,omitempty"`
+	Primary *bool                `scim:"primary" json:"primary,omitempty"`
+}
+
+// MarshalSCIM converts the UserPhoneNumber into a SCIM resource.
+func (v UserPhoneNumber) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.Value != nil {
+		resource["value"] = *v.Value
+	}
+	if v.Type != nil {
+		resource["type"] = string(*v.Type)
+	}
+	if v.Primary != nil {
+		resource["primary"] = *v.Primary
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the UserPhoneNumber with the given SCIM resource.
+func (v *UserPhoneNumber) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["value"].(type) {
+	case nil:
+	case string:
+		valueValue := value
+		v.Value = &valueValue
+	default:
+		return fmt.Errorf("types of \"value\" do not match: got %T, want string", value)
+	}
+	switch value := resource["type"].(type) {
+	case nil:
+	case string:
+		typeValue := UserPhoneNumberType(value)
+		v.Type = &typeValue
+	default:
+		return fmt.Errorf("types of \"type\" do not match: got %T, want string", value)
+	}
+	switch value := resource["primary"].(type) {
+	case nil:
+	case bool:
+		primaryValue := value
+		v.Primary = &primaryValue
+	default:
+		return fmt.Errorf("types of \"primary\" do not match: got %T, want bool", value)
+	}
+	return nil
+}
+
+// Validate checks whether the UserPhoneNumber conforms to the schema it was generated from.
+func (v UserPhoneNumber) Validate() error {
+	if v.Type != nil {
+		if !v.Type.IsValid() {
+			return fmt.Errorf("value of \"type\" is not a canonical value: %q", *v.Type)
+		}
+	}
+	return nil
+}
+
+// UserPhoneNumberType represents the canonical values of the "type" attribute.
+type UserPhoneNumberType string
+
+const (
+	UserPhoneNumberTypeWork   UserPhoneNumberType = "work"
+	UserPhoneNumberTypeHome   UserPhoneNumberType = "home"
+	UserPhoneNumberTypeMobile UserPhoneNumberType = "mobile"
+	UserPhoneNumberTypeFax    UserPhoneNumberType = "fax"
+	UserPhoneNumberTypePager  UserPhoneNumberType = "pager"
+	UserPhoneNumberTypeOther  UserPhoneNumberType = "other"
+)
+
+// IsValid checks whether the UserPhoneNumberType is one of the canonical values.
+func (v UserPhoneNumberType) IsValid() bool {
+	switch v {
+	case UserPhoneNumberTypeWork, UserPhoneNumberTypeHome, UserPhoneNumberTypeMobile, UserPhoneNumberTypeFax, UserPhoneNumberTypePager, UserPhoneNumberTypeOther:
+		return true
+	}
+	return false
+}
+
+type UserX509Certificate struct {
+	Value []byte `scim:"value" json:"value,omitempty"`
+}
+
+// MarshalSCIM converts the UserX509Certificate into a SCIM resource.
+func (v UserX509Certificate) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if len(v.Value) != 0 {
+		resource["value"] = base64.StdEncoding.EncodeToString(v.Value)
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the UserX509Certificate with the given SCIM resource.
+func (v *UserX509Certificate) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["value"].(type) {
+	case nil:
+	case string:
+		parsed, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return fmt.Errorf("value of \"value\" is not a valid binary: %v", err)
+		}
+		v.Value = parsed
+	case []byte:
+		v.Value = value
+	default:
+		return fmt.Errorf("types of \"value\" do not match: got %T, want string", value)
+	}
+	return nil
+}
+
+// Validate checks whether the UserX509Certificate conforms to the schema it was generated from.
+func (v UserX509Certificate) Validate() error {
+	return nil
+}
+
+// Enterprise User
+type EnterpriseUserExtension struct {
+	EmployeeNumber *string                         `scim:"employeeNumber" json:"employeeNumber,omitempty"`
+	Manager        *EnterpriseUserExtensionManager `scim:"manager" json:"manager,omitempty"`
+}
+
+// MarshalSCIM converts the EnterpriseUserExtension into a SCIM resource.
+func (v EnterpriseUserExtension) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.EmployeeNumber != nil {
+		resource["employeeNumber"] = *v.EmployeeNumber
+	}
+	if v.Manager != nil {
+		managerValue, err := v.Manager.MarshalSCIM()
+		if err != nil {
+			return nil, err
+		}
+		if len(managerValue) == 0 {
+			resource["manager"] = nil
+		} else {
+			resource["manager"] = managerValue
+		}
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the EnterpriseUserExtension with the given SCIM resource.
+func (v *EnterpriseUserExtension) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["employeeNumber"].(type) {
+	case nil:
+	case string:
+		employeeNumberValue := value
+		v.EmployeeNumber = &employeeNumberValue
+	default:
+		return fmt.Errorf("types of \"employeeNumber\" do not match: got %T, want string", value)
+	}
+	switch value := resource["manager"].(type) {
+	case nil:
+		if _, ok := resource["manager"]; ok {
+			v.Manager = new(EnterpriseUserExtensionManager)
+		}
+	case map[string]interface{}:
+		var element EnterpriseUserExtensionManager
+		if err := element.UnmarshalSCIM(value); err != nil {
+			return err
+		}
+		managerValue := element
+		v.Manager = &managerValue
+	default:
+		return fmt.Errorf("types of \"manager\" do not match: got %T, want map[string]interface{}", value)
+	}
+	return nil
+}
+
+// Validate checks whether the EnterpriseUserExtension conforms to the schema it was generated from.
+func (v EnterpriseUserExtension) Validate() error {
+	if v.Manager != nil {
+		if err := v.Manager.Validate(); err != nil {
+			return fmt.Errorf("manager: %w", err)
+		}
+	}
+	return nil
+}
+
+type EnterpriseUserExtensionManager struct {
+	Value       *string                            `scim:"value" json:"value,omitempty"`
+	Ref         *EnterpriseUserExtensionManagerRef `scim:"$ref" json:"$ref,omitempty"`
+	DisplayName *string                            `scim:"displayName" json:"displayName,omitempty"`
+}
+
+// MarshalSCIM converts the EnterpriseUserExtensionManager into a SCIM resource.
+func (v EnterpriseUserExtensionManager) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.Value != nil {
+		resource["value"] = *v.Value
+	}
+	if v.Ref != nil {
+		resource["$ref"] = string(*v.Ref)
+	}
+	if v.DisplayName != nil {
+		resource["displayName"] = *v.DisplayName
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the EnterpriseUserExtensionManager with the given SCIM resource.
+func (v *EnterpriseUserExtensionManager) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["value"].(type) {
+	case nil:
+	case string:
+		valueValue := value
+		v.Value = &valueValue
+	default:
+		return fmt.Errorf("types of \"value\" do not match: got %T, want string", value)
+	}
+	switch value := resource["$ref"].(type) {
+	case nil:
+	case string:
+		refValue := EnterpriseUserExtensionManagerRef(value)
+		v.Ref = &refValue
+	default:
+		return fmt.Errorf("types of \"$ref\" do not match: got %T, want string", value)
+	}
+	switch value := resource["displayName"].(type) {
+	case nil:
+	case string:
+		displayNameValue := value
+		v.DisplayName = &displayNameValue
+	default:
+		return fmt.Errorf("types of \"displayName\" do not match: got %T, want string", value)
+	}
+	return nil
+}
+
+// Validate checks whether the EnterpriseUserExtensionManager conforms to the schema it was generated from.
+func (v EnterpriseUserExtensionManager) Validate() error {
+	return nil
+}
+
+// EnterpriseUserExtensionManagerRef represents a reference of the "$ref" attribute.
+type EnterpriseUserExtensionManagerRef string
+
+// ReferenceTypes returns the types of the resources that a EnterpriseUserExtensionManagerRef can reference.
+func (EnterpriseUserExtensionManagerRef) ReferenceTypes() []string {
+	return []string{"User"}
+}
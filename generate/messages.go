@@ -0,0 +1,55 @@
+package generate
+
+// messagesFile is the name of the file that contains the messages used by the generated client and server.
+const messagesFile = "messages.go"
+
+// messagesImports are the imports of the generated messages.
+var messagesImports = map[string]string{
+	"github.com/scim2/tools/marshal/messages": "messages",
+}
+
+// messagesBase contains the messages (and helpers) that are shared by the generated client and server. The messages
+// are those of the marshal/messages package, so that they are the same for every generated package.
+const messagesBase = `// Error is an error response of the service provider.
+type Error = messages.Error
+
+// Query contains the parameters of a list or search request.
+type Query = messages.SearchRequest
+
+// PatchOperation is an operation of a PATCH request. The value can be a resource (or complex attribute) of this package.
+type PatchOperation = messages.PatchOperation
+
+// marshaler is implemented by the resources (and complex attributes) of this package.
+type marshaler interface {
+	MarshalSCIM() (map[string]interface{}, error)
+}
+
+// unmarshaler is implemented by the resources (and complex attributes) of this package, and the messages.
+type unmarshaler interface {
+	UnmarshalSCIM(map[string]interface{}) error
+}
+
+// encode converts the given resource. If it does not contain any schemas, the core schema and the schemas of the
+// extensions that are present get added.
+func encode(resource marshaler, core string, extensions ...string) (map[string]interface{}, error) {
+	data, err := resource.MarshalSCIM()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := data["schemas"]; !ok && core != "" {
+		schemas := []interface{}{core}
+		for _, e := range extensions {
+			if _, ok := data[e]; ok {
+				schemas = append(schemas, e)
+			}
+		}
+		data["schemas"] = schemas
+	}
+	return data, nil
+}
+`
+
+// generateMessages generates the messages that are used by the client and server.
+func (p *PackageGenerator) generateMessages() ([]byte, error) {
+	return generateFile(p.pkg, messagesImports, []byte(messagesBase))
+}
@@ -0,0 +1,261 @@
+package generate
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/scim2/tools/schema"
+)
+
+// Naming converts the names of schemas and attributes into go identifiers.
+type Naming interface {
+	// Identifier converts the given name of a schema or attribute into an exported go identifier, e.g. "userName" into
+	// "UserName".
+	Identifier(name string) string
+	// Singular returns the singular of the given identifier, e.g. "UserAddresses" into "UserAddress". It is used for the
+	// types of the values of multi valued attributes.
+	Singular(identifier string) string
+}
+
+// irregularPlurals maps plural words on their singular. Words that end with an "s" in their singular form are mapped on
+// themselves, so that they stay the same.
+var irregularPlurals = map[string]string{
+	"aliases":  "alias",
+	"analyses": "analysis",
+	"bonuses":  "bonus",
+	"buses":    "bus",
+	"children": "child",
+	"criteria": "criterion",
+	"data":     "data",
+	"indices":  "index",
+	"men":      "man",
+	"people":   "person",
+	"statuses": "status",
+	"women":    "woman",
+	"news":     "news",
+	"series":   "series",
+	"species":  "species",
+}
+
+// DefaultNaming is the default naming strategy of the generators. Identifiers are the capitalized names without the
+// characters that are not allowed in identifiers, prefixed by an "X" if they would start with a digit. Singulars are
+// based on a table of irregular plurals, extended with the given Plurals, and the common English suffixes.
+type DefaultNaming struct {
+	// Plurals maps additional (lower case) plural words on their singular, e.g. {"geese": "goose"}.
+	Plurals map[string]string
+}
+
+func (n DefaultNaming) Identifier(name string) string {
+	identifier := cap(keepAlpha(name))
+	if identifier != "" && unicode.IsDigit(rune(identifier[0])) {
+		identifier = "X" + identifier
+	}
+	return identifier
+}
+
+// Singular converts the last word of the given identifier, e.g. "Policies" in "UserPolicies", into its singular.
+func (n DefaultNaming) Singular(identifier string) string {
+	i := strings.LastIndexFunc(identifier, unicode.IsUpper)
+	if i == -1 {
+		i = 0
+	}
+	prefix, word := identifier[:i], identifier[i:]
+	lower := strings.ToLower(word)
+
+	singular, ok := n.Plurals[lower]
+	if !ok {
+		singular, ok = irregularPlurals[lower]
+	}
+	if !ok {
+		switch {
+		case strings.HasSuffix(lower, "ies") && len(lower) > 3:
+			singular = strings.TrimSuffix(lower, "ies") + "y"
+		case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"),
+			strings.HasSuffix(lower, "shes"), strings.HasSuffix(lower, "zzes"):
+			singular = strings.TrimSuffix(lower, "es")
+		case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"), strings.HasSuffix(lower, "is"):
+			singular = lower
+		case strings.HasSuffix(lower, "s"):
+			singular = strings.TrimSuffix(lower, "s")
+		default:
+			singular = lower
+		}
+	}
+	if singular == "" {
+		return identifier
+	}
+	if word != lower {
+		// Keep the case of the first letter, e.g. "Policies" into "Policy".
+		singular = strings.ToUpper(singular[:1]) + singular[1:]
+	}
+	return prefix + singular
+}
+
+// Naming sets the naming strategy of the generator, the default is DefaultNaming.
+func (g *StructGenerator) Naming(n Naming) *StructGenerator {
+	g.naming = n
+	return g
+}
+
+// Names overrides the names of the fields of the given attributes. The attributes are referenced by their path, e.g.
+// "userName", "name.givenName" or "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:employeeNumber". The
+// names of the types of the attributes are derived from the new names. Paths that do not match any attribute result in
+// an error when the file is generated.
+func (g *StructGenerator) Names(names map[string]string) *StructGenerator {
+	g.names, g.unknownNames = make(map[*schema.Attribute]string), nil
+	if len(names) == 0 {
+		return g
+	}
+
+	paths := make(map[string]string, len(names))
+	for path, name := range names {
+		paths[strings.ToLower(path)] = name
+	}
+	var walk func(prefix string, attrs []*schema.Attribute)
+	walk = func(prefix string, attrs []*schema.Attribute) {
+		for _, attr := range attrs {
+			path := prefix + attr.Name
+			if name, ok := paths[strings.ToLower(path)]; ok {
+				g.names[attr] = name
+				delete(paths, strings.ToLower(path))
+			}
+			walk(path+".", attr.SubAttributes)
+		}
+	}
+	walk("", g.s.Attributes)
+	for _, e := range g.e {
+		walk(e.ID+":", e.Attributes)
+	}
+	for path := range paths {
+		g.unknownNames = append(g.unknownNames, path)
+	}
+	sort.Strings(g.unknownNames)
+	return g
+}
+
+// identifier converts the given name into an identifier with the naming strategy of the generator.
+func (g *StructGenerator) identifier(name string) string {
+	if g.naming == nil {
+		return DefaultNaming{}.Identifier(name)
+	}
+	return g.naming.Identifier(name)
+}
+
+// singular returns the singular of the given identifier with the naming strategy of the generator.
+func (g *StructGenerator) singular(identifier string) string {
+	if g.naming == nil {
+		return DefaultNaming{}.Singular(identifier)
+	}
+	return g.naming.Singular(identifier)
+}
+
+// fieldName returns the name of the field of the given attribute.
+func (g *StructGenerator) fieldName(attr *schema.Attribute) string {
+	if name, ok := g.names[attr]; ok {
+		return name
+	}
+	return g.identifier(attr.Name)
+}
+
+// resourceName returns the name of the struct of the resource.
+func (g *StructGenerator) resourceName() string {
+	return g.identifier(g.s.Name)
+}
+
+// extensionName returns the name of the field of the given extension, the name of its struct has an additional
+// "Extension" suffix.
+func (g *StructGenerator) extensionName(e schema.ReferenceSchema) string {
+	return g.identifier(e.Name)
+}
+
+// checkNames checks whether all the names of the generated code are valid exported identifiers, and whether the names
+// of the fields within every struct and the names of the types are unique.
+func (g *StructGenerator) checkNames() error {
+	if len(g.unknownNames) != 0 {
+		return fmt.Errorf("names of unknown attributes: %s", strings.Join(g.unknownNames, ", "))
+	}
+
+	types := make(map[string]string)
+	addType := func(typ, source string) error {
+		if err := checkIdentifier(typ, source); err != nil {
+			return err
+		}
+		if other, ok := types[typ]; ok {
+			return fmt.Errorf("%s and %s both have the type name %s", other, source, typ)
+		}
+		types[typ] = source
+		return nil
+	}
+
+	var checkStruct func(name, source string, attrs []*schema.Attribute, extensions []schema.ReferenceSchema) error
+	checkStruct = func(name, source string, attrs []*schema.Attribute, extensions []schema.ReferenceSchema) error {
+		if err := addType(name, source); err != nil {
+			return err
+		}
+		fields := make(map[string]string)
+		addField := func(field, source string) error {
+			if err := checkIdentifier(field, source); err != nil {
+				return err
+			}
+			if other, ok := fields[field]; ok {
+				return fmt.Errorf("%s and %s both have the field name %s.%s", other, source, name, field)
+			}
+			fields[field] = source
+			return nil
+		}
+
+		for _, attr := range attrs {
+			f := g.field(name, attr)
+			attrSource := fmt.Sprintf("attribute %q", attr.Name)
+			if err := addField(f.name, attrSource); err != nil {
+				return err
+			}
+			if f.custom {
+				continue
+			}
+			if _, shared := g.shared[attr]; attr.Type == schema.ComplexType && !shared {
+				if err := checkStruct(f.typ, attrSource, attr.SubAttributes, nil); err != nil {
+					return err
+				}
+				continue
+			}
+			if isCanonical(attr) || attr.Type == schema.ReferenceType {
+				if err := addType(f.typ, attrSource); err != nil {
+					return err
+				}
+			}
+			if isCanonical(attr) {
+				for _, v := range attr.CanonicalValues {
+					if err := addType(f.canonicalValue(v), fmt.Sprintf("canonical value %q of %s", v, attrSource)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		for _, e := range extensions {
+			source := fmt.Sprintf("extension %q", e.ID)
+			if err := addField(g.extensionName(e), source); err != nil {
+				return err
+			}
+			if g.external[e.ID] {
+				continue
+			}
+			if err := checkStruct(g.extensionName(e)+"Extension", source, e.Attributes, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return checkStruct(g.resourceName(), fmt.Sprintf("schema %q", g.s.Name), g.s.Attributes, g.e)
+}
+
+// checkIdentifier checks whether the given name is a valid exported identifier.
+func checkIdentifier(name, source string) error {
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return fmt.Errorf("%s: %q is not a valid exported identifier", source, name)
+	}
+	return nil
+}
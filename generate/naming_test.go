@@ -0,0 +1,185 @@
+package generate_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/scim2/tools/generate"
+	"github.com/scim2/tools/schema"
+)
+
+func TestDefaultNaming_Singular(t *testing.T) {
+	n := generate.DefaultNaming{Plurals: map[string]string{"geese": "goose"}}
+	for plural, singular := range map[string]string{
+		"UserEmails":           "UserEmail",
+		"UserAddresses":        "UserAddress",
+		"UserAliases":          "UserAlias",
+		"UserStatus":           "UserStatus",
+		"UserStatuses":         "UserStatus",
+		"UserPolicies":         "UserPolicy",
+		"UserBoxes":            "UserBox",
+		"UserClasses":          "UserClass",
+		"UserClass":            "UserClass",
+		"UserResponses":        "UserResponse",
+		"UserChildren":         "UserChild",
+		"UserX509Certificates": "UserX509Certificate",
+		"UserURLs":             "UserURL",
+		"UserGeese":            "UserGoose",
+		"roles":                "role",
+	} {
+		if s := n.Singular(plural); s != singular {
+			t.Errorf("expected %s for %s, got %s", singular, plural, s)
+		}
+	}
+}
+
+func TestDefaultNaming_Identifier(t *testing.T) {
+	for name, identifier := range map[string]string{
+		"userName":        "UserName",
+		"externalId":      "ExternalID",
+		"$ref":            "Ref",
+		"2fa":             "X2fa",
+		"Enterprise User": "EnterpriseUser",
+	} {
+		if i := (generate.DefaultNaming{}).Identifier(name); i != identifier {
+			t.Errorf("expected %s for %s, got %s", identifier, name, i)
+		}
+	}
+}
+
+// upperNaming converts names into upper case identifiers.
+type upperNaming struct {
+	generate.DefaultNaming
+}
+
+func (upperNaming) Identifier(name string) string {
+	return strings.ToUpper(name)
+}
+
+func TestStructGenerator_names(t *testing.T) {
+	for _, test := range []struct {
+		attrs  []*schema.Attribute
+		naming generate.Naming
+		names  map[string]string
+		pkg    string
+		err    string
+	}{
+		{
+			attrs: []*schema.Attribute{{Name: "ref"}, {Name: "$ref"}},
+			err:   `attribute "$ref" and attribute "ref" both have the field name User.Ref`,
+		},
+		{
+			attrs: []*schema.Attribute{
+				{Name: "email", Type: schema.ComplexType, SubAttributes: []*schema.Attribute{{Name: "value"}}},
+				{Name: "emails", Type: schema.ComplexType, MultiValued: true, SubAttributes: []*schema.Attribute{{Name: "value"}}},
+			},
+			err: `attribute "email" and attribute "emails" both have the type name UserEmail`,
+		},
+		{
+			attrs: []*schema.Attribute{{Name: "type", CanonicalValues: []string{"work", "Work"}}},
+			err:   `canonical value "work" of attribute "type" and canonical value "Work" of attribute "type" both have the type name UserTypeWork`,
+		},
+		{
+			attrs: []*schema.Attribute{{Name: "userName"}, {Name: "displayName"}},
+			names: map[string]string{"displayName": "UserName"},
+			err:   `attribute "displayName" and attribute "userName" both have the field name User.UserName`,
+		},
+		{
+			attrs: []*schema.Attribute{{Name: "userName"}},
+			names: map[string]string{"userName": "user-name"},
+			err:   `attribute "userName": "user-name" is not a valid exported identifier`,
+		},
+		{
+			attrs:  []*schema.Attribute{{Name: "user name"}},
+			naming: upperNaming{},
+			err:    `attribute "user name": "USER NAME" is not a valid exported identifier`,
+		},
+		{
+			attrs: []*schema.Attribute{{Name: "userName"}},
+			names: map[string]string{"username": "Name", "nickName": "Nick"},
+			err:   `names of unknown attributes: nickname`,
+		},
+		{
+			pkg: "type",
+			err: `invalid package name: "type"`,
+		},
+	} {
+		g, err := generate.NewStructGenerator(schema.ReferenceSchema{Name: "User", Attributes: test.attrs})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.pkg == "" {
+			test.pkg = "scim"
+		}
+		g.Package(test.pkg).Naming(test.naming).Names(test.names)
+		if _, err := g.GenerateFile(); err == nil || err.Error() != test.err {
+			t.Errorf("expected error %q, got %v", test.err, err)
+		}
+	}
+}
+
+func ExampleStructGenerator_Names() {
+	g, _ := generate.NewStructGenerator(schema.ReferenceSchema{
+		Name: "User",
+		Attributes: []*schema.Attribute{
+			{
+				Name:        "statuses",
+				MultiValued: true,
+				Type:        schema.ComplexType,
+				SubAttributes: []*schema.Attribute{
+					{Name: "value"},
+				},
+			},
+			{
+				Name: "name",
+				Type: schema.ComplexType,
+				SubAttributes: []*schema.Attribute{
+					{Name: "givenName"},
+				},
+			},
+			{Name: "2fa", Type: schema.BooleanType},
+		},
+	})
+	g.Names(map[string]string{
+		"name":           "FullName",
+		"name.givenName": "FirstName",
+	})
+	fmt.Print(g.Generate())
+
+	// Output:
+	// type User struct {
+	//     X2fa       bool
+	//     ExternalID string
+	//     ID         string
+	//     Meta       UserMeta
+	//     FullName   UserFullName
+	//     Schemas    []string
+	//     Statuses   []UserStatus
+	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	//     ResourceType string
+	//     Created      time.Time
+	//     LastModified time.Time
+	//     Location     UserMetaLocation
+	//     Version      string
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	//     return []string{"uri"}
+	// }
+	//
+	// type UserFullName struct {
+	//     FirstName string
+	// }
+	//
+	// type UserStatus struct {
+	//     Value string
+	// }
+}
@@ -0,0 +1,249 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/scim2/tools/schema"
+)
+
+// sharedFile is the name of the file that contains the types that are shared by the resources of a package.
+const sharedFile = "shared.go"
+
+// PackageGenerator generates the structs of multiple resources into a single package. Complex types that are
+// structurally identical, such as the meta attribute of every resource or the {value, display, type, primary} types of
+// multi valued attributes, are only generated once and shared by all the resources (and extensions).
+type PackageGenerator struct {
+	pkg        string
+	generators []*StructGenerator
+
+	client    bool
+	server    bool
+	endpoints map[string]string
+}
+
+// NewPackageGenerator returns a PackageGenerator that generates the resources of the given struct generators into the
+// package with the given name. The options of the struct generators are used to generate their resources.
+func NewPackageGenerator(pkg string, generators ...StructGenerator) PackageGenerator {
+	p := PackageGenerator{
+		pkg:        pkg,
+		generators: make([]*StructGenerator, len(generators)),
+	}
+	for i := range generators {
+		p.generators[i] = &generators[i]
+	}
+	return p
+}
+
+// Client indicates whether the generator will also generate a typed client for the resources in "client.go". The
+// client is built on net/http and the MarshalSCIM and UnmarshalSCIM methods of the resources, so this enables the
+// codecs of all the struct generators.
+func (p *PackageGenerator) Client(t bool) *PackageGenerator {
+	p.client = t
+	return p
+}
+
+// Server indicates whether the generator will also generate an http.Handler and a store interface for every resource
+// in "server.go". The store interfaces have to be implemented by the service provider, the handlers take care of the
+// routing, the parsing of the requests and the (error) responses. This enables the codecs of all the struct generators.
+func (p *PackageGenerator) Server(t bool) *PackageGenerator {
+	p.server = t
+	return p
+}
+
+// Endpoints sets the endpoints of the resources by their names, relative to the base URL of the service provider,
+// e.g. {"User": "/Users"}. The endpoint of a resource defaults to the plural of its name.
+func (p *PackageGenerator) Endpoints(endpoints map[string]string) *PackageGenerator {
+	p.endpoints = endpoints
+	return p
+}
+
+// occurrence represents a complex attribute of a resource or extension.
+type occurrence struct {
+	g    *StructGenerator
+	attr *schema.Attribute
+	// typ is the name of the type of the attribute.
+	typ string
+	// meta indicates whether the attribute is the meta attribute of a resource.
+	meta bool
+}
+
+// Generate creates the files of the package, it returns a map of file names to their content. Every resource gets its
+// own file, named after the resource. The shared types are generated in "shared.go", the client in "client.go" and the
+// handlers in "server.go". The messages that are used by both the client and the handlers are generated in
+// "messages.go".
+// Extensions that are used by multiple resources are generated in the file of the first resource.
+func (p *PackageGenerator) Generate() (map[string][]byte, error) {
+	if p.pkg == "" {
+		return nil, errors.New("package name is not set")
+	}
+
+	// names contains the names of the structs of the resources and extensions.
+	names := make(map[string]bool)
+	extensions := make(map[string]bool)
+
+	var signatures []string
+	occurrences := make(map[string][]occurrence)
+	for _, g := range p.generators {
+		g.shared, g.external = nil, make(map[string]bool)
+		if p.client || p.server {
+			g.codecs = true
+		}
+
+		type structAttributes struct {
+			name  string
+			attrs []*schema.Attribute
+		}
+		structs := []structAttributes{{g.resourceName(), g.s.Attributes}}
+		for _, e := range g.e {
+			if extensions[e.ID] {
+				g.external[e.ID] = true
+				continue
+			}
+			extensions[e.ID] = true
+			structs = append(structs, structAttributes{g.extensionName(e) + "Extension", e.Attributes})
+		}
+
+		for i, s := range structs {
+			names[s.name] = true
+			for _, attr := range s.attrs {
+				if f := g.field(s.name, attr); f.isComplex() && !f.custom {
+					signature := g.signature(attr)
+					if _, ok := occurrences[signature]; !ok {
+						signatures = append(signatures, signature)
+					}
+					occurrences[signature] = append(occurrences[signature], occurrence{
+						g:    g,
+						attr: attr,
+						typ:  f.typ,
+						meta: i == 0 && strings.EqualFold(attr.Name, schema.MetaAttribute.Name),
+					})
+				}
+			}
+		}
+	}
+
+	shared := make(map[*schema.Attribute]string)
+	var types []occurrence
+	for _, signature := range signatures {
+		o := occurrences[signature]
+		if len(o) < 2 && !o[0].meta {
+			continue
+		}
+
+		// Attributes with the same name share a type named after the attribute (e.g. Meta), others use the type name
+		// of the first occurrence.
+		name, desc := o[0].typ, ""
+		if common := commonName(o); common != "" && !names[common] {
+			name, desc = common, o[0].attr.Description
+		}
+		names[name] = true
+		for _, o := range o {
+			shared[o.attr] = name
+		}
+		types = append(types, occurrence{
+			g:    o[0].g,
+			attr: &schema.Attribute{Description: desc, SubAttributes: o[0].attr.SubAttributes},
+			typ:  name,
+		})
+	}
+
+	files := make(map[string][]byte)
+	for _, g := range p.generators {
+		g.shared = shared
+		file, err := g.Package(p.pkg).GenerateFile()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", g.s.Name, err)
+		}
+		name := strings.ToLower(g.resourceName()) + ".go"
+		if _, ok := files[name]; ok || isGeneratedFile(name) {
+			return nil, fmt.Errorf("duplicate file name: %s", name)
+		}
+		files[name] = file
+	}
+
+	if len(types) != 0 {
+		var body bytes.Buffer
+		imports := make(map[string]string)
+		for i, t := range types {
+			g := *t.g
+			g.w, g.imports = newGenWriter(&body), imports
+			if g.codecs {
+				g.addImport("fmt", "fmt")
+			}
+			if i != 0 {
+				g.w.n()
+			}
+			g.generateStruct(t.typ, t.attr.Description, t.attr.SubAttributes, false)
+		}
+		file, err := generateFile(p.pkg, imports, body.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		files[sharedFile] = file
+	}
+
+	if p.client || p.server {
+		file, err := p.generateMessages()
+		if err != nil {
+			return nil, err
+		}
+		files[messagesFile] = file
+	}
+	if p.client {
+		file, err := p.generateClient()
+		if err != nil {
+			return nil, err
+		}
+		files[clientFile] = file
+	}
+	if p.server {
+		file, err := p.generateServer()
+		if err != nil {
+			return nil, err
+		}
+		files[serverFile] = file
+	}
+	return files, nil
+}
+
+// isGeneratedFile checks whether the file with the given name is generated by the package generator itself, these names
+// can not be used by the resources.
+func isGeneratedFile(name string) bool {
+	switch name {
+	case sharedFile, messagesFile, clientFile, serverFile:
+		return true
+	}
+	return false
+}
+
+// signature returns the go code of the type of the given complex attribute, independent of the name of the attribute.
+// Attributes with the same signature can share a type.
+func (g *StructGenerator) signature(attr *schema.Attribute) string {
+	w, imports := g.w, g.imports
+	defer func() {
+		g.w, g.imports = w, imports
+	}()
+
+	g.w, g.imports = newGenWriter(&bytes.Buffer{}), nil
+	g.generateStruct("Shared", "", attr.SubAttributes, false)
+	return g.w.writer.(*bytes.Buffer).String()
+}
+
+// commonName returns the name of the type of the given attributes if they all have the same name, otherwise it returns
+// an empty string.
+func commonName(occurrences []occurrence) string {
+	for _, o := range occurrences {
+		if !strings.EqualFold(o.attr.Name, occurrences[0].attr.Name) {
+			return ""
+		}
+	}
+	g := occurrences[0].g
+	name := g.fieldName(occurrences[0].attr)
+	if occurrences[0].attr.MultiValued {
+		name = g.singular(name)
+	}
+	return name
+}
@@ -0,0 +1,221 @@
+package generate_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/scim2/tools/generate"
+	"github.com/scim2/tools/schema"
+)
+
+func TestPackageGenerator(t *testing.T) {
+	value := func(name string) *schema.Attribute {
+		return &schema.Attribute{
+			Name:          name,
+			Type:          schema.ComplexType,
+			MultiValued:   true,
+			SubAttributes: []*schema.Attribute{{Name: "value"}, {Name: "display"}},
+		}
+	}
+	enterprise := schema.ReferenceSchema{
+		ID:         "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User",
+		Name:       "Enterprise User",
+		Attributes: []*schema.Attribute{value("managers")},
+	}
+	user, _ := generate.NewStructGenerator(schema.ReferenceSchema{
+		Name:       "User",
+		Attributes: []*schema.Attribute{value("roles"), value("entitlements")},
+	}, enterprise)
+	admin, _ := generate.NewStructGenerator(schema.ReferenceSchema{
+		Name: "Admin",
+	}, enterprise)
+	p := generate.NewPackageGenerator("scim", user, admin)
+	files, err := p.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, contains := range map[string][]string{
+		"user.go": {
+			"Entitlements []UserEntitlement",
+			"Roles        []UserEntitlement",
+			"type EnterpriseUserExtension struct",
+			"Managers []UserEntitlement",
+		},
+		"admin.go": {
+			"EnterpriseUser EnterpriseUserExtension",
+		},
+		"shared.go": {
+			"type UserEntitlement struct",
+			"type Meta struct",
+		},
+	} {
+		file := string(files[name])
+		for _, s := range contains {
+			if !strings.Contains(file, s) {
+				t.Errorf("expected %q in %s:\n%s", s, name, file)
+			}
+		}
+	}
+	if strings.Contains(string(files["admin.go"]), "type EnterpriseUserExtension struct") {
+		t.Error("extension is generated twice")
+	}
+
+	p = generate.NewPackageGenerator("")
+	if _, err := p.Generate(); err == nil {
+		t.Error("error expected, got none")
+	}
+}
+
+func ExamplePackageGenerator() {
+	emails := func() *schema.Attribute {
+		return &schema.Attribute{
+			Name:        "emails",
+			Type:        schema.ComplexType,
+			MultiValued: true,
+			SubAttributes: []*schema.Attribute{
+				{Name: "value"},
+				{Name: "primary", Type: schema.BooleanType},
+			},
+		}
+	}
+	user, _ := generate.NewStructGenerator(schema.ReferenceSchema{
+		Name:       "User",
+		Attributes: []*schema.Attribute{emails()},
+	})
+	group, _ := generate.NewStructGenerator(schema.ReferenceSchema{
+		Name:       "Group",
+		Attributes: []*schema.Attribute{emails()},
+	})
+	p := generate.NewPackageGenerator("scim", user, group)
+	files, _ := p.Generate()
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s:\n%s\n", name, files[name])
+	}
+
+	// Output:
+	// group.go:
+	// // Code generated by github.com/scim2/tools/generate. DO NOT EDIT.
+	//
+	// package scim
+	//
+	// type Group struct {
+	// 	Emails     []Email
+	// 	ExternalID string
+	// 	ID         string
+	// 	Meta       Meta
+	// 	Schemas    []string
+	// }
+	//
+	// shared.go:
+	// // Code generated by github.com/scim2/tools/generate. DO NOT EDIT.
+	//
+	// package scim
+	//
+	// import (
+	// 	"time"
+	// )
+	//
+	// type Email struct {
+	// 	Value   string
+	// 	Primary bool
+	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type Meta struct {
+	// 	ResourceType string
+	// 	Created      time.Time
+	// 	LastModified time.Time
+	// 	Location     MetaLocation
+	// 	Version      string
+	// }
+	//
+	// // MetaLocation represents a reference of the "location" attribute.
+	// type MetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a MetaLocation can reference.
+	// func (MetaLocation) ReferenceTypes() []string {
+	// 	return []string{"uri"}
+	// }
+	//
+	// user.go:
+	// // Code generated by github.com/scim2/tools/generate. DO NOT EDIT.
+	//
+	// package scim
+	//
+	// type User struct {
+	// 	Emails     []Email
+	// 	ExternalID string
+	// 	ID         string
+	// 	Meta       Meta
+	// 	Schemas    []string
+	// }
+}
+
+func TestPackageGenerator_Client(t *testing.T) {
+	user, _ := generate.NewStructGenerator(schema.ReferenceSchema{Name: "User"})
+	policy, _ := generate.NewStructGenerator(schema.ReferenceSchema{Name: "Policy"})
+	p := generate.NewPackageGenerator("scim", user, policy)
+	p.Client(true).Endpoints(map[string]string{"User": "Accounts"})
+	files, err := p.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := string(files["client.go"])
+	for _, s := range []string{
+		"func (c *Client) Users() *UserClient {",
+		`c.client.list(ctx, "/Accounts", query, &list)`,
+		"func (c *Client) Policies() *PolicyClient {",
+		`c.client.do(ctx, http.MethodDelete, "/Policies/"+url.PathEscape(id), nil, nil)`,
+	} {
+		if !strings.Contains(client, s) {
+			t.Errorf("expected %q in client.go", s)
+		}
+	}
+	if !strings.Contains(string(files["user.go"]), "func (v User) MarshalSCIM()") {
+		t.Error("expected codecs in user.go")
+	}
+}
+
+func TestPackageGenerator_Server(t *testing.T) {
+	user, _ := generate.NewStructGenerator(schema.ReferenceSchema{Name: "User"})
+	policy, _ := generate.NewStructGenerator(schema.ReferenceSchema{Name: "Policy"})
+	p := generate.NewPackageGenerator("scim", user, policy)
+	p.Server(true).Endpoints(map[string]string{"User": "Accounts"})
+	files, err := p.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := files["client.go"]; ok {
+		t.Error("unexpected client.go")
+	}
+	if _, ok := files["messages.go"]; !ok {
+		t.Error("expected messages.go")
+	}
+
+	server := string(files["server.go"])
+	for _, s := range []string{
+		"type UserStore interface {",
+		"func NewUserHandler(store UserStore) *UserHandler {",
+		`serve(h, "/Accounts", w, r)`,
+		"type PolicyStore interface {",
+		`serve(h, "/Policies", w, r)`,
+		"decoded.ID = id",
+	} {
+		if !strings.Contains(server, s) {
+			t.Errorf("expected %q in server.go", s)
+		}
+	}
+	if strings.Contains(server, "Validate()") {
+		t.Error("unexpected validation in server.go")
+	}
+}
@@ -0,0 +1,41 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scim2/tools/schema"
+)
+
+// generateReferenceTypes generates a named string type for every reference attribute of the struct with the given
+// name. If the attribute has reference types, the type gets a ReferenceTypes method that returns them.
+func (g *StructGenerator) generateReferenceTypes(name string, attrs []*schema.Attribute) {
+	w := g.w
+
+	for _, attr := range attrs {
+		f := g.field(name, attr)
+		if f.custom || attr.Type != schema.ReferenceType {
+			continue
+		}
+
+		// Canonical types are already generated.
+		if !isCanonical(attr) {
+			w.n()
+			w.lnf("// %s represents a reference of the %q attribute.", f.typ, attr.Name)
+			w.lnf("type %s %s", f.typ, f.kind)
+		}
+		if len(attr.ReferenceTypes) == 0 {
+			continue
+		}
+
+		types := make([]string, len(attr.ReferenceTypes))
+		for i, t := range attr.ReferenceTypes {
+			types[i] = fmt.Sprintf("%q", t)
+		}
+		w.n()
+		w.lnf("// ReferenceTypes returns the types of the resources that a %s can reference.", f.typ)
+		w.lnf("func (%s) ReferenceTypes() []string {", f.typ)
+		w.in(4).lnf("return []string{%s}", strings.Join(types, ", "))
+		w.ln("}")
+	}
+}
@@ -0,0 +1,425 @@
+package generate
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/scim2/tools/schema"
+)
+
+// serverFile is the name of the file that contains the generated handlers.
+const serverFile = "server.go"
+
+// serverImports are the imports of the generated handlers.
+var serverImports = map[string]string{
+	"context":       "context",
+	"encoding/json": "json",
+	"errors":        "errors",
+	"fmt":           "fmt",
+	"net/http":      "http",
+	"net/url":       "url",
+	"strconv":       "strconv",
+	"strings":       "strings",
+
+	"github.com/scim2/tools/marshal/messages": "messages",
+}
+
+// serverBase contains the part of the handlers that does not depend on the resources.
+const serverBase = `// resourceHandler is implemented by the handlers of the resources of this package. The resources are passed in their
+// marshalled form, so that the routing and the responses can be shared by all the handlers.
+type resourceHandler interface {
+	get(ctx context.Context, id string) (map[string]interface{}, error)
+	list(ctx context.Context, query Query) ([]map[string]interface{}, int, error)
+	create(ctx context.Context, resource map[string]interface{}) (map[string]interface{}, error)
+	replace(ctx context.Context, id string, resource map[string]interface{}) (map[string]interface{}, error)
+	patch(ctx context.Context, id string, ops []PatchOperation) (map[string]interface{}, error)
+	delete(ctx context.Context, id string) error
+}
+
+// serve routes the given request to the given handler. The path of the request is expected to start with the endpoint,
+// handlers that are mounted on a base path should be wrapped with http.StripPrefix.
+func serve(h resourceHandler, endpoint string, w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != endpoint && !strings.HasPrefix(r.URL.Path, endpoint+"/") {
+		writeError(w, &Error{Status: http.StatusNotFound, Detail: fmt.Sprintf("unknown endpoint: %s", r.URL.Path)})
+		return
+	}
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, endpoint), "/")
+	if strings.Contains(id, "/") {
+		writeError(w, &Error{Status: http.StatusNotFound, Detail: fmt.Sprintf("unknown endpoint: %s", r.URL.Path)})
+		return
+	}
+
+	ctx := r.Context()
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		query, err := parseQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeList(w, h, r, query)
+	case id == ".search" && r.Method == http.MethodPost:
+		body, err := readBody(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		query, err := parseSearchRequest(body)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeList(w, h, r, query)
+	case id == "" && r.Method == http.MethodPost:
+		body, err := readBody(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resource, err := h.create(ctx, body)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if meta, ok := resource["meta"].(map[string]interface{}); ok {
+			if location, ok := meta["location"].(string); ok {
+				w.Header().Set("Location", location)
+			}
+		}
+		writeResource(w, http.StatusCreated, resource)
+	case id == "" || id == ".search":
+		writeError(w, methodNotAllowed(r))
+	case r.Method == http.MethodGet:
+		resource, err := h.get(ctx, id)
+		if err == nil && resource == nil {
+			err = &Error{Status: http.StatusNotFound, Detail: fmt.Sprintf("resource %q not found", id)}
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResource(w, http.StatusOK, resource)
+	case r.Method == http.MethodPut:
+		body, err := readBody(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resource, err := h.replace(ctx, id, body)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResource(w, http.StatusOK, resource)
+	case r.Method == http.MethodPatch:
+		body, err := readBody(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		ops, err := parsePatchRequest(body)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resource, err := h.patch(ctx, id, ops)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResource(w, http.StatusOK, resource)
+	case r.Method == http.MethodDelete:
+		if err := h.delete(ctx, id); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, methodNotAllowed(r))
+	}
+}
+
+// methodNotAllowed returns the error of a request with an unsupported method.
+func methodNotAllowed(r *http.Request) *Error {
+	return &Error{Status: http.StatusMethodNotAllowed, Detail: fmt.Sprintf("method %s is not allowed on %s", r.Method, r.URL.Path)}
+}
+
+// parseQuery parses the query parameters of a list request, the attributes are given as comma separated lists.
+func parseQuery(values url.Values) (Query, error) {
+	params := make(map[string]interface{})
+	for _, k := range []string{"filter", "sortBy", "sortOrder"} {
+		if v := values.Get(k); v != "" {
+			params[k] = v
+		}
+	}
+	for _, k := range []string{"attributes", "excludedAttributes"} {
+		var attrs []interface{}
+		for _, attr := range strings.Split(values.Get(k), ",") {
+			if attr = strings.TrimSpace(attr); attr != "" {
+				attrs = append(attrs, attr)
+			}
+		}
+		if len(attrs) != 0 {
+			params[k] = attrs
+		}
+	}
+	for _, k := range []string{"startIndex", "count"} {
+		if v := values.Get(k); v != "" {
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				return Query{}, &Error{Status: http.StatusBadRequest, SCIMType: messages.InvalidValue, Detail: fmt.Sprintf("%s is not an integer: %q", k, v)}
+			}
+			params[k] = float64(i)
+		}
+	}
+	return parseSearchRequest(params)
+}
+
+// parseSearchRequest parses the body of a search request. The start index is 1 if not specified (or less than 1), the
+// count is nil if not specified and 0 if negative.
+func parseSearchRequest(body map[string]interface{}) (Query, error) {
+	var query Query
+	if err := query.UnmarshalSCIM(body); err != nil {
+		return Query{}, badRequest(err, messages.InvalidValue)
+	}
+	if query.SortOrder != "" && query.SortOrder != messages.Ascending && query.SortOrder != messages.Descending {
+		return Query{}, &Error{Status: http.StatusBadRequest, SCIMType: messages.InvalidValue, Detail: fmt.Sprintf("invalid sort order: %q", query.SortOrder)}
+	}
+	if query.StartIndex < 1 {
+		query.StartIndex = 1
+	}
+	if query.Count != nil && *query.Count < 0 {
+		*query.Count = 0
+	}
+	return query, nil
+}
+
+// parsePatchRequest parses the operations of a PatchOp request. The values of the operations are not decoded.
+func parsePatchRequest(body map[string]interface{}) ([]PatchOperation, error) {
+	var request messages.PatchOp
+	if err := request.UnmarshalSCIM(body); err != nil {
+		return nil, badRequest(err, messages.InvalidSyntax)
+	}
+	return request.Operations, nil
+}
+
+// badRequest converts the given error of a request message into a SCIM error with the given SCIM type, unless it is a
+// SCIM error already.
+func badRequest(err error, scimType string) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return &Error{Status: http.StatusBadRequest, SCIMType: scimType, Detail: err.Error()}
+}
+
+// readBody decodes the JSON body of the given request.
+func readBody(r *http.Request) (map[string]interface{}, error) {
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, &Error{Status: http.StatusBadRequest, SCIMType: messages.InvalidSyntax, Detail: fmt.Sprintf("invalid request body: %v", err)}
+	}
+	return body, nil
+}
+
+// writeList writes the resources that match the given query as a ListResponse.
+func writeList(w http.ResponseWriter, h resourceHandler, r *http.Request, query Query) {
+	resources, total, err := h.list(r.Context(), query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	list, err := messages.ListResponse[map[string]interface{}]{
+		TotalResults: total,
+		ItemsPerPage: len(resources),
+		StartIndex:   query.StartIndex,
+		Resources:    resources,
+	}.MarshalSCIM()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// writeResource writes the given resource, a nil resource results in a response without content.
+func writeResource(w http.ResponseWriter, status int, resource map[string]interface{}) {
+	if resource == nil {
+		if status == http.StatusOK {
+			status = http.StatusNoContent
+		}
+		w.WriteHeader(status)
+		return
+	}
+	writeJSON(w, status, resource)
+}
+
+// writeError writes the given error as a SCIM error response. Errors that are not of type *Error result in an internal
+// server error, their details are not exposed. Errors without a valid status are sent as an internal server error.
+func writeError(w http.ResponseWriter, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = &Error{Status: http.StatusInternalServerError, Detail: http.StatusText(http.StatusInternalServerError)}
+	} else if e.Status < 100 || e.Status > 999 {
+		// WriteHeader panics on invalid status codes, errors without a valid status are internal server errors.
+		e = &Error{Status: http.StatusInternalServerError, SCIMType: e.SCIMType, Detail: e.Detail}
+	}
+	resp, _ := e.MarshalSCIM()
+	writeJSON(w, e.Status, resp)
+}
+
+// writeJSON writes the given value as the JSON body of the response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+`
+
+// generateServer generates the handlers and store interfaces of the resources of the package.
+func (p *PackageGenerator) generateServer() ([]byte, error) {
+	w := newGenWriter(&bytes.Buffer{})
+	w.w(serverBase)
+
+	for _, g := range p.generators {
+		name := g.resourceName()
+		store := name + "Store"
+		handler := name + "Handler"
+		endpoint := p.endpoint(g)
+
+		w.n()
+		w.lnf("// %s stores the %s resources that are served by the %s. Errors of type *Error are returned to the", store, name, handler)
+		w.ln("// client as is, other errors result in an internal server error.")
+		w.lnf("type %s interface {", store)
+		w.in(4).lnf("// Get returns the %s with the given id.", name)
+		w.in(4).lnf("Get(ctx context.Context, id string) (*%s, error)", name)
+		w.in(4).lnf("// List returns the page of %s resources that match the query, together with the total number of matches.", name)
+		w.in(4).lnf("List(ctx context.Context, query Query) ([]%s, int, error)", name)
+		w.in(4).lnf("// Create creates the given %s and returns it as created.", name)
+		w.in(4).lnf("Create(ctx context.Context, resource %s) (*%s, error)", name, name)
+		w.in(4).lnf("// Replace replaces the %s with the given id and returns it as replaced.", name)
+		w.in(4).lnf("Replace(ctx context.Context, id string, resource %s) (*%s, error)", name, name)
+		w.in(4).lnf("// Patch modifies the %s with the given id. It returns the modified %s, or nil to respond without", name, name)
+		w.in(4).ln("// content. The values of the operations are the decoded JSON values of the request.")
+		w.in(4).lnf("Patch(ctx context.Context, id string, ops []PatchOperation) (*%s, error)", name)
+		w.in(4).lnf("// Delete deletes the %s with the given id.", name)
+		w.in(4).ln("Delete(ctx context.Context, id string) error")
+		w.ln("}")
+		w.n()
+		w.lnf("// %s serves the %s resources at %q, including searches at %q.", handler, name, endpoint, endpoint+"/.search")
+		w.lnf("type %s struct {", handler)
+		w.in(4).lnf("store %s", store)
+		w.ln("}")
+		w.n()
+		w.lnf("// New%s returns a handler that serves the %s resources in the given store.", handler, name)
+		w.lnf("func New%s(store %s) *%s {", handler, store, handler)
+		w.in(4).lnf("return &%s{store: store}", handler)
+		w.ln("}")
+		w.n()
+		w.lnf("func (h *%s) ServeHTTP(w http.ResponseWriter, r *http.Request) {", handler)
+		w.in(4).lnf("serve(h, %q, w, r)", endpoint)
+		w.ln("}")
+		w.n()
+		w.lnf("func (h *%s) get(ctx context.Context, id string) (map[string]interface{}, error) {", handler)
+		w.in(4).ln("resource, err := h.store.Get(ctx, id)")
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("return h.encode(resource)")
+		w.ln("}")
+		w.n()
+		w.lnf("func (h *%s) list(ctx context.Context, query Query) ([]map[string]interface{}, int, error) {", handler)
+		w.in(4).ln("resources, total, err := h.store.List(ctx, query)")
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, 0, err")
+		w.in(4).ln("}")
+		w.in(4).ln("encoded := make([]map[string]interface{}, len(resources))")
+		w.in(4).ln("for i := range resources {")
+		w.in(8).ln("if encoded[i], err = h.encode(&resources[i]); err != nil {")
+		w.in(12).ln("return nil, 0, err")
+		w.in(8).ln("}")
+		w.in(4).ln("}")
+		w.in(4).ln("return encoded, total, nil")
+		w.ln("}")
+		w.n()
+		w.lnf("func (h *%s) create(ctx context.Context, resource map[string]interface{}) (map[string]interface{}, error) {", handler)
+		w.in(4).ln("decoded, err := h.decode(\"\", resource)")
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("created, err := h.store.Create(ctx, decoded)")
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("return h.encode(created)")
+		w.ln("}")
+		w.n()
+		w.lnf("func (h *%s) replace(ctx context.Context, id string, resource map[string]interface{}) (map[string]interface{}, error) {", handler)
+		w.in(4).ln("decoded, err := h.decode(id, resource)")
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("replaced, err := h.store.Replace(ctx, id, decoded)")
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("return h.encode(replaced)")
+		w.ln("}")
+		w.n()
+		w.lnf("func (h *%s) patch(ctx context.Context, id string, ops []PatchOperation) (map[string]interface{}, error) {", handler)
+		w.in(4).ln("patched, err := h.store.Patch(ctx, id, ops)")
+		w.in(4).ln("if err != nil {")
+		w.in(8).ln("return nil, err")
+		w.in(4).ln("}")
+		w.in(4).ln("return h.encode(patched)")
+		w.ln("}")
+		w.n()
+		w.lnf("func (h *%s) delete(ctx context.Context, id string) error {", handler)
+		w.in(4).ln("return h.store.Delete(ctx, id)")
+		w.ln("}")
+		w.n()
+		id := g.idField()
+		w.lnf("// decode converts the body of a request into a %s.", name)
+		if id != nil {
+			w.ln("// The id is assigned by the service provider, the id in the body is replaced by the given id.")
+		}
+		w.lnf("func (h *%s) decode(id string, resource map[string]interface{}) (%s, error) {", handler, name)
+		w.in(4).lnf("var decoded %s", name)
+		w.in(4).ln("if err := decoded.UnmarshalSCIM(resource); err != nil {")
+		w.in(8).lnf("return %s{}, &Error{Status: http.StatusBadRequest, SCIMType: messages.InvalidValue, Detail: err.Error()}", name)
+		w.in(4).ln("}")
+		if id != nil {
+			w.in(4).lnf("decoded.%s = id", id.name)
+		}
+		if g.validation {
+			w.in(4).ln("if err := decoded.Validate(); err != nil {")
+			w.in(8).lnf("return %s{}, &Error{Status: http.StatusBadRequest, SCIMType: messages.InvalidValue, Detail: err.Error()}", name)
+			w.in(4).ln("}")
+		}
+		w.in(4).ln("return decoded, nil")
+		w.ln("}")
+		w.n()
+		w.lnf("// encode converts the given %s into the body of a response, it returns nil if the %s is nil.", name, name)
+		w.lnf("func (h *%s) encode(resource *%s) (map[string]interface{}, error) {", handler, name)
+		w.in(4).ln("if resource == nil {")
+		w.in(8).ln("return nil, nil")
+		w.in(4).ln("}")
+		w.in(4).lnf("return encode(resource, %s)", p.schemas(g))
+		w.ln("}")
+	}
+	return generateFile(p.pkg, serverImports, w.writer.(*bytes.Buffer).Bytes())
+}
+
+// idField returns the field of the id attribute of the resource. It returns nil if the id is not a plain string, e.g.
+// if it has a custom type.
+func (g *StructGenerator) idField() *field {
+	for _, attr := range g.s.Attributes {
+		if !strings.EqualFold(attr.Name, schema.IDAttribute.Name) {
+			continue
+		}
+		if f := g.field(g.resourceName(), attr); f.kind == "string" && f.typ == "string" && !f.custom && !f.ptr && !attr.MultiValued {
+			return &f
+		}
+	}
+	return nil
+}
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"go/format"
+	"go/token"
+	"path"
 	"sort"
 	"strings"
 
@@ -15,13 +18,32 @@ type StructGenerator struct {
 	s schema.ReferenceSchema
 	e []schema.ReferenceSchema
 
-	ptr         bool
-	addTags     func(a *schema.Attribute) map[string]string
-	customTypes map[string]CustomType
+	ptr            bool
+	codecs         bool
+	validation     bool
+	scimTags       bool
+	jsonTags       bool
+	pkg            string
+	addTags        func(a *schema.Attribute) map[string]string
+	customTypes    map[string]CustomType
+	attributeTypes map[schema.Type]CustomType
+	naming         Naming
+	// names maps the attributes of which the names are overridden on their field names.
+	names map[*schema.Attribute]string
+	// unknownNames contains the paths of the overridden names that do not match any attribute.
+	unknownNames []string
+
+	// imports maps the import paths used by the generated code on their package names.
+	imports map[string]string
+	// shared maps the complex attributes of which the types are generated by a PackageGenerator on their type names.
+	shared map[*schema.Attribute]string
+	// external contains the IDs of the extensions that are generated in another file of the package.
+	external map[string]bool
 }
 
 type CustomType struct {
 	PkgPrefix string // package id
+	PkgPath   string // import path of the package, defaults to the package id
 	AttrName  string // name of the attribute
 	TypeName  string // name of the custom type
 }
@@ -40,22 +62,18 @@ func NewStructGenerator(s schema.ReferenceSchema, extensions ...schema.Reference
 		})
 	}
 
-	// check if id an externalId is present, add if not
-	var id, eid bool
-	for _, a := range s.Attributes {
-		if strings.ToLower(a.Name) == "id" {
-			id = true
+	// check if the common attributes (schemas, id, externalId and meta) are present, add if not
+	for _, c := range schema.CoreAttributes {
+		var present bool
+		for _, a := range s.Attributes {
+			if strings.EqualFold(a.Name, c.Name) {
+				present = true
+			}
 		}
-		if strings.ToLower(a.Name) == "externalid" {
-			eid = true
+		if !present {
+			s.Attributes = append(s.Attributes, c)
 		}
 	}
-	if !id {
-		s.Attributes = append(s.Attributes, schema.IDAttribute)
-	}
-	if !eid {
-		s.Attributes = append(s.Attributes, schema.ExternalIDAttribute)
-	}
 
 	sort.SliceStable(s.Attributes, func(i, j int) bool {
 		return strings.ToLower(s.Attributes[i].Name) < strings.ToLower(s.Attributes[j].Name)
@@ -75,6 +93,36 @@ func (g *StructGenerator) UsePtr(t bool) *StructGenerator {
 	return g
 }
 
+// Codecs indicates whether the generator will also generate MarshalSCIM and UnmarshalSCIM methods for every struct.
+// These methods implement the marshal.Marshaler and marshal.Unmarshaler interfaces without using reflection. Fields
+// with custom types are marshaled as is and unmarshaled from strings, numbers and booleans through their JSON
+// representation, complex custom types need to implement these interfaces themselves.
+func (g *StructGenerator) Codecs(t bool) *StructGenerator {
+	g.codecs = t
+	return g
+}
+
+// Validation indicates whether the generator will also generate a Validate method for every struct. This method checks
+// the required attributes, the canonical values, the uniqueness of multi valued attributes (or their "value" sub
+// attributes) and whether there is at most one primary value.
+func (g *StructGenerator) Validation(t bool) *StructGenerator {
+	g.validation = t
+	return g
+}
+
+// SCIMTags indicates whether the generator will add scim tags to the fields. These tags contain the name of the
+// attribute and whether it is multi valued, so the structs can be used by the marshal package.
+func (g *StructGenerator) SCIMTags(t bool) *StructGenerator {
+	g.scimTags = t
+	return g
+}
+
+// JSONTags indicates whether the generator will add json tags, containing the name of the attribute, to the fields.
+func (g *StructGenerator) JSONTags(t bool) *StructGenerator {
+	g.jsonTags = t
+	return g
+}
+
 // AddTags enables setting fields tags when the attribute is has certain attribute fields such as required.
 func (g *StructGenerator) AddTags(f func(a *schema.Attribute) (tags map[string]string)) *StructGenerator {
 	g.addTags = f
@@ -93,113 +141,311 @@ func (g *StructGenerator) CustomTypes(types []CustomType) *StructGenerator {
 	return g
 }
 
-// Generate creates a buffer with a go representation of the resource described in the given schema.
+// AttributeTypes overrides the go types that are used for the attributes of the given types. By default dateTime
+// attributes are represented by time.Time, binary attributes by []byte and reference attributes by a named string type
+// with a ReferenceTypes method. These are supported by both the marshal package and the generated codecs. Complex
+// attribute types can not be overridden, CustomTypes takes precedence over these types.
+func (g *StructGenerator) AttributeTypes(types map[schema.Type]CustomType) *StructGenerator {
+	g.attributeTypes = types
+	return g
+}
+
+// Package sets the name of the package used by GenerateFile.
+func (g *StructGenerator) Package(name string) *StructGenerator {
+	g.pkg = name
+	return g
+}
+
+// Generate creates a buffer with a go representation of the resource described in the given schema. The names are not
+// checked, use GenerateFile to detect names that collide or that are not valid identifiers.
 func (g *StructGenerator) Generate() *bytes.Buffer {
-	g.generateStruct(g.s.Name, g.s.Description, g.s.Attributes, true)
+	g.w = newGenWriter(&bytes.Buffer{})
+	g.imports = make(map[string]string)
+	if g.codecs {
+		g.addImport("fmt", "fmt")
+	}
+
+	g.generateStruct(g.resourceName(), g.s.Description, g.s.Attributes, true)
 	for _, e := range g.e {
+		if g.external[e.ID] {
+			continue
+		}
 		g.w.n()
-		g.generateStruct(e.Name+"Extension", e.Description, e.Attributes, false)
+		g.generateStruct(g.extensionName(e)+"Extension", e.Description, e.Attributes, false)
 	}
 	return g.w.writer.(*bytes.Buffer)
 }
 
+// GenerateFile creates a complete go file of the resource described in the given schema. The file contains a header
+// that marks it as generated, the package clause and all the imports that are needed. It is formatted with go/format.
+func (g *StructGenerator) GenerateFile() ([]byte, error) {
+	if g.pkg == "" {
+		return nil, errors.New("package name is not set")
+	}
+	if !token.IsIdentifier(g.pkg) {
+		return nil, fmt.Errorf("invalid package name: %q", g.pkg)
+	}
+	if err := g.checkNames(); err != nil {
+		return nil, err
+	}
+	body := g.Generate()
+	return generateFile(g.pkg, g.imports, body.Bytes())
+}
+
+// generateFile creates a go file of the given package with the given imports and body, formatted with go/format.
+func generateFile(pkg string, imports map[string]string, body []byte) ([]byte, error) {
+	w := newGenWriter(&bytes.Buffer{})
+	w.ln("// Code generated by github.com/scim2/tools/generate. DO NOT EDIT.")
+	w.n()
+	w.lnf("package %s", pkg)
+	w.n()
+
+	if len(imports) != 0 {
+		paths := make([]string, 0, len(imports))
+		for p := range imports {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+
+		// The imports of the standard library come first, the others are grouped after them.
+		sort.SliceStable(paths, func(i, j int) bool {
+			return isStandardImport(paths[i]) && !isStandardImport(paths[j])
+		})
+
+		w.ln("import (")
+		for i, p := range paths {
+			if i != 0 && isStandardImport(paths[i-1]) && !isStandardImport(p) {
+				w.n()
+			}
+			if name := imports[p]; name != path.Base(p) {
+				w.in(4).lnf("%s %q", name, p)
+			} else {
+				w.in(4).lnf("%q", p)
+			}
+		}
+		w.ln(")")
+		w.n()
+	}
+
+	buf := w.writer.(*bytes.Buffer)
+	buf.Write(body)
+	return format.Source(buf.Bytes())
+}
+
+// isStandardImport checks whether the given import path is a package of the standard library, its first element does
+// not contain a dot.
+func isStandardImport(importPath string) bool {
+	return !strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".")
+}
+
+// addImport adds the given import path to the imports of the generated file.
+func (g *StructGenerator) addImport(importPath, name string) {
+	if g.imports != nil {
+		g.imports[importPath] = name
+	}
+}
+
 func (g *StructGenerator) generateStruct(name, desc string, attrs []*schema.Attribute, core bool) {
 	w := g.w
 
-	name = keepAlpha(name) // remove all non alpha characters
-
 	if desc != "" {
 		w.ln(comment(wrap(desc, 117))) // 120 - "// "
 	}
 
 	if len(attrs) == 0 {
 		w.lnf("type %s struct {}", name)
+		if g.codecs {
+			g.generateCodecs(name, attrs, core)
+		}
+		if g.validation {
+			g.generateValidate(name, attrs, core)
+		}
 		return
 	}
 
 	w.lnf("type %s struct {", name)
 	g.generateStructFields(name, attrs, core)
 	w.ln("}")
+	if g.codecs {
+		g.generateCodecs(name, attrs, core)
+	}
+	if g.validation {
+		g.generateValidate(name, attrs, core)
+	}
+	g.generateCanonicalTypes(name, attrs)
+	g.generateReferenceTypes(name, attrs)
 
 	for _, attr := range attrs {
-		_, custom := g.customTypes[attr.Name]
-		if attr.Type == schema.ComplexType && !custom {
-			typ := cap(attr.Name)
-			if attr.MultiValued {
-				typ = singular(typ)
-			}
+		f := g.field(name, attr)
+		if _, shared := g.shared[attr]; attr.Type == schema.ComplexType && !f.custom && !shared {
 			w.n()
-			g.generateStruct(name+typ, attr.Description, attr.SubAttributes, false)
+			g.generateStruct(f.typ, attr.Description, attr.SubAttributes, false)
 		}
 	}
 }
 
-func (g *StructGenerator) generateStructFields(name string, attrs []*schema.Attribute, core bool) {
-	w := g.w
-
-	name = keepAlpha(name) // remove all non alpha characters
-
-	// get longest name to indent fields.
-	var indent int
-	for _, attr := range attrs {
-		if l := len(cap(attr.Name)); l > indent {
-			indent = l
+// fieldTags returns the tags of the field of the given attribute, preceded by a space. The scim and json tags come
+// first, followed by the tags of AddTags in alphabetical order.
+func (g *StructGenerator) fieldTags(attr *schema.Attribute) string {
+	var keys []string
+	tags := make(map[string]string)
+	if g.scimTags {
+		keys = append(keys, "scim")
+		tags["scim"] = attr.Name
+		if attr.MultiValued {
+			tags["scim"] += ",mV"
+		}
+	}
+	if g.jsonTags {
+		keys = append(keys, "json")
+		tags["json"] = attr.Name + ",omitempty"
+	}
+	if g.addTags != nil {
+		var custom []string
+		for k, v := range g.addTags(attr) {
+			if _, ok := tags[k]; !ok {
+				custom = append(custom, k)
+			}
+			tags[k] = v
 		}
+		sort.Strings(custom)
+		keys = append(keys, custom...)
+	}
+	if len(keys) == 0 {
+		return ""
 	}
 
-	for _, attr := range attrs {
-		var typ string
-		switch t := attr.Type; t {
-		case "decimal":
-			typ = "float64"
-		case "integer":
-			typ = "int"
-		case "boolean":
-			typ = "bool"
-		case "complex":
-			typ = cap(name + cap(attr.Name))
-		default:
-			typ = "string"
+	var tag string
+	for _, k := range keys {
+		if v := tags[k]; v != "" {
+			tag += fmt.Sprintf("%s:%q ", k, v)
+		} else {
+			tag += fmt.Sprintf("%s ", k)
 		}
+	}
+	return fmt.Sprintf(" `%s`", strings.TrimSuffix(tag, " "))
+}
 
-		// field name
-		name := cap(keepAlpha(attr.Name))
-		w.in(4).w(name)
-		w.sp(indent - len(name) + 1)
+// field represents the go field of an attribute.
+type field struct {
+	attr *schema.Attribute
+	// name of the field.
+	name string
+	// typ is the type of the field, or the type of the elements if the attribute is multi valued.
+	typ string
+	// kind is the underlying simple go type of typ, it is empty for complex and custom types.
+	kind string
+	// custom indicates whether the type is a custom type.
+	custom bool
+	// ptr indicates whether the field is a pointer.
+	ptr bool
+}
 
-		if attr.MultiValued {
-			w.w("[]")
-			typ = singular(typ)
-		} else if !attr.Required && g.ptr {
-			w.w("*")
+// field returns the field representing the given attribute within the struct with the given name.
+func (g *StructGenerator) field(name string, attr *schema.Attribute) field {
+	fieldName := g.fieldName(attr)
+	var typ, kind string
+	switch t := attr.Type; t {
+	case "decimal":
+		kind = "float64"
+	case "integer":
+		kind = "int"
+	case "boolean":
+		kind = "bool"
+	case "complex":
+		typ = name + fieldName
+	case "dateTime":
+		kind = "time.Time"
+	case "binary":
+		kind = "[]byte"
+	case "reference":
+		kind = "string"
+		typ = name + fieldName
+	default:
+		kind = "string"
+		if isCanonical(attr) {
+			typ = name + fieldName
 		}
+	}
+	if typ == "" {
+		typ = kind
+	}
+	if attr.MultiValued {
+		typ = g.singular(typ)
+	}
+	if shared, ok := g.shared[attr]; ok {
+		typ = shared
+	}
 
-		if t, custom := g.customTypes[attr.Name]; custom {
-			if t.PkgPrefix != "" {
-				typ = fmt.Sprintf("%s.%s", t.PkgPrefix, t.TypeName)
+	t, custom := g.customTypes[attr.Name]
+	if !custom && attr.Type != schema.ComplexType {
+		t, custom = g.attributeTypes[attr.Type]
+	}
+	if custom {
+		kind = ""
+		if t.PkgPrefix != "" {
+			typ = fmt.Sprintf("%s.%s", t.PkgPrefix, t.TypeName)
+			if t.PkgPath != "" {
+				g.addImport(t.PkgPath, t.PkgPrefix)
 			} else {
-				typ = t.TypeName
+				g.addImport(t.PkgPrefix, t.PkgPrefix)
 			}
+		} else {
+			typ = t.TypeName
 		}
+	} else if kind == "time.Time" {
+		g.addImport("time", "time")
+	}
 
-		if g.addTags != nil {
-			tags := g.addTags(attr)
-			w.w(typ)
-			var tag string
-			if tags != nil && len(tags) != 0 {
-				for k, v := range tags {
-					if v != "" {
-						tag += fmt.Sprintf("%s:%q ", k, v)
-					} else {
-						tag += fmt.Sprintf("%s ", k)
-					}
-				}
-				tag = strings.TrimSuffix(tag, " ")
-				tag = fmt.Sprintf(" `%s`", tag)
-			}
-			w.ln(tag)
+	return field{
+		attr:   attr,
+		name:   fieldName,
+		typ:    typ,
+		kind:   kind,
+		custom: custom,
+		ptr:    !attr.MultiValued && !attr.Required && g.ptr && kind != "[]byte",
+	}
+}
+
+// fullType returns the type of the field, including the slice or pointer prefix.
+func (f field) fullType() string {
+	if f.attr.MultiValued {
+		return "[]" + f.typ
+	} else if f.ptr {
+		return "*" + f.typ
+	}
+	return f.typ
+}
+
+func (g *StructGenerator) generateStructFields(name string, attrs []*schema.Attribute, core bool) {
+	w := g.w
+
+	// get longest name to indent fields and longest type to align the tags.
+	fields := make([]field, 0, len(attrs))
+	var indent, indentT int
+	for _, attr := range attrs {
+		f := g.field(name, attr)
+		if l := len(f.name); l > indent {
+			indent = l
+		}
+		if l := len(f.fullType()); l > indentT {
+			indentT = l
+		}
+		fields = append(fields, f)
+	}
+
+	for _, f := range fields {
+		// field name
+		w.in(4).w(f.name)
+		w.sp(indent - len(f.name) + 1)
+
+		typ := f.fullType()
+		w.w(typ)
+		if tags := g.fieldTags(f.attr); tags != "" {
+			w.sp(indentT - len(typ))
+			w.ln(tags)
 		} else {
-			w.ln(typ)
+			w.n()
 		}
 	}
 
@@ -211,18 +457,27 @@ func (g *StructGenerator) generateStructFields(name string, attrs []*schema.Attr
 
 		var indentE int
 		for _, e := range g.e {
-			if l := len(cap(keepAlpha(e.Name))); l > indentE {
+			if l := len(g.extensionName(e)); l > indentE {
 				indentE = l
 			}
 		}
 		for _, e := range g.e {
-			name := cap(keepAlpha(e.Name))
+			name := g.extensionName(e)
 			w.in(4).w(name)
 			w.sp(indentE - len(name) + 1)
 			typ := name + "Extension"
 			w.w(typ)
 			w.sp(indentE - len(typ) + 9)
-			w.lnf(" `scim:%q`", e.ID)
+			tag := e.ID
+			if g.scimTags {
+				// Extensions are marshalled as separate resources.
+				tag += ",inline"
+			}
+			if g.jsonTags {
+				w.lnf(" `scim:%q json:\"%s,omitempty\"`", tag, e.ID)
+			} else {
+				w.lnf(" `scim:%q`", tag)
+			}
 		}
 	}
 }
@@ -40,7 +40,26 @@ func ExampleStructGenerator_AddTags() {
 	// // User Account
 	// type User struct {
 	//     ExternalID string
-	//     ID         string `x:"required,unique"`
+	//     ID         string   `x:"required,unique"`
+	//     Meta       UserMeta
+	//     Schemas    []string `x:"required"`
+	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	//     ResourceType string
+	//     Created      time.Time
+	//     LastModified time.Time
+	//     Location     UserMetaLocation
+	//     Version      string
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	//     return []string{"uri"}
 	// }
 }
 
@@ -68,6 +87,7 @@ func ExampleStructGenerator_CustomTypes() {
 	//     ExternalID string
 	//     ID         uuid.UUID
 	//     Meta       Meta
+	//     Schemas    []string
 	// }
 }
 
@@ -83,6 +103,25 @@ func ExampleStructGenerator_Generate_empty() {
 	// type User struct {
 	//     ExternalID string
 	//     ID         string
+	//     Meta       UserMeta
+	//     Schemas    []string
+	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	//     ResourceType string
+	//     Created      time.Time
+	//     LastModified time.Time
+	//     Location     UserMetaLocation
+	//     Version      string
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	//     return []string{"uri"}
 	// }
 }
 
@@ -103,8 +142,27 @@ func ExampleStructGenerator_Generate_minimal() {
 	// type User struct {
 	//     ExternalID string
 	//     ID         string
+	//     Meta       UserMeta
+	//     Schemas    []string
 	//     UserName   string
 	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	//     ResourceType string
+	//     Created      time.Time
+	//     LastModified time.Time
+	//     Location     UserMetaLocation
+	//     Version      string
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	//     return []string{"uri"}
+	// }
 }
 
 func ExampleStructGenerator_Generate_extensions() {
@@ -133,12 +191,671 @@ func ExampleStructGenerator_Generate_extensions() {
 	// type User struct {
 	//     ExternalID string
 	//     ID         string
+	//     Meta       UserMeta
+	//     Schemas    []string
 	//     UserName   string
 	//
 	//     EnterpriseUser EnterpriseUserExtension `scim:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"`
 	// }
 	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	//     ResourceType string
+	//     Created      time.Time
+	//     LastModified time.Time
+	//     Location     UserMetaLocation
+	//     Version      string
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	//     return []string{"uri"}
+	// }
+	//
 	// type EnterpriseUserExtension struct {
 	//     EmployeeNumber string
 	// }
 }
+
+func ExampleStructGenerator_SCIMTags() {
+	g, _ := generate.NewStructGenerator(
+		schema.ReferenceSchema{
+			Name: "User",
+			Attributes: []*schema.Attribute{
+				{Name: "userName", Required: true},
+				{Name: "emails", MultiValued: true, Type: schema.ComplexType, SubAttributes: []*schema.Attribute{
+					{Name: "value"},
+				}},
+			},
+		},
+		schema.ReferenceSchema{
+			ID:   "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User",
+			Name: "Enterprise User",
+			Attributes: []*schema.Attribute{
+				{Name: "employeeNumber"},
+			},
+		},
+	)
+	g.SCIMTags(true).JSONTags(true)
+	fmt.Print(g.Generate())
+
+	// Output:
+	// type User struct {
+	//     Emails     []UserEmail `scim:"emails,mV" json:"emails,omitempty"`
+	//     ExternalID string      `scim:"externalId" json:"externalId,omitempty"`
+	//     ID         string      `scim:"id" json:"id,omitempty"`
+	//     Meta       UserMeta    `scim:"meta" json:"meta,omitempty"`
+	//     Schemas    []string    `scim:"schemas,mV" json:"schemas,omitempty"`
+	//     UserName   string      `scim:"userName" json:"userName,omitempty"`
+	//
+	//     EnterpriseUser EnterpriseUserExtension `scim:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,inline" json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,omitempty"`
+	// }
+	//
+	// type UserEmail struct {
+	//     Value string `scim:"value" json:"value,omitempty"`
+	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	//     ResourceType string           `scim:"resourceType" json:"resourceType,omitempty"`
+	//     Created      time.Time        `scim:"created" json:"created,omitempty"`
+	//     LastModified time.Time        `scim:"lastModified" json:"lastModified,omitempty"`
+	//     Location     UserMetaLocation `scim:"location" json:"location,omitempty"`
+	//     Version      string           `scim:"version" json:"version,omitempty"`
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	//     return []string{"uri"}
+	// }
+	//
+	// type EnterpriseUserExtension struct {
+	//     EmployeeNumber string `scim:"employeeNumber" json:"employeeNumber,omitempty"`
+	// }
+}
+
+func ExampleStructGenerator_Validation() {
+	g, _ := generate.NewStructGenerator(schema.ReferenceSchema{
+		Name: "User",
+		Attributes: []*schema.Attribute{
+			{Name: "userName", Required: true},
+			{Name: "emails", MultiValued: true, Type: schema.ComplexType, SubAttributes: []*schema.Attribute{
+				{Name: "value", Uniqueness: schema.Server},
+				{Name: "primary", Type: schema.BooleanType},
+			}},
+		},
+	})
+	g.Validation(true)
+	fmt.Print(g.Generate())
+
+	// Output:
+	// type User struct {
+	//     Emails     []UserEmail
+	//     ExternalID string
+	//     ID         string
+	//     Meta       UserMeta
+	//     Schemas    []string
+	//     UserName   string
+	// }
+	//
+	// // Validate checks whether the User conforms to the schema it was generated from.
+	// func (v User) Validate() error {
+	//     emailsSeen := make(map[string]bool, len(v.Emails))
+	//     var emailsPrimary bool
+	//     for _, value := range v.Emails {
+	//         if err := value.Validate(); err != nil {
+	//             return fmt.Errorf("emails: %w", err)
+	//         }
+	//         if emailsSeen[value.Value] {
+	//             return fmt.Errorf("duplicate value of \"emails\": %v", value.Value)
+	//         }
+	//         emailsSeen[value.Value] = true
+	//         if value.Primary {
+	//             if emailsPrimary {
+	//                 return errors.New("multiple primary values of \"emails\"")
+	//             }
+	//             emailsPrimary = true
+	//         }
+	//     }
+	//     if v.Meta != (UserMeta{}) {
+	//         if err := v.Meta.Validate(); err != nil {
+	//             return fmt.Errorf("meta: %w", err)
+	//         }
+	//     }
+	//     if len(v.Schemas) == 0 {
+	//         return errors.New("required attribute \"schemas\" is missing")
+	//     }
+	//     if v.UserName == "" {
+	//         return errors.New("required attribute \"userName\" is missing")
+	//     }
+	//     return nil
+	// }
+	//
+	// type UserEmail struct {
+	//     Value   string
+	//     Primary bool
+	// }
+	//
+	// // Validate checks whether the UserEmail conforms to the schema it was generated from.
+	// func (v UserEmail) Validate() error {
+	//     return nil
+	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	//     ResourceType string
+	//     Created      time.Time
+	//     LastModified time.Time
+	//     Location     UserMetaLocation
+	//     Version      string
+	// }
+	//
+	// // Validate checks whether the UserMeta conforms to the schema it was generated from.
+	// func (v UserMeta) Validate() error {
+	//     return nil
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	//     return []string{"uri"}
+	// }
+}
+
+func ExampleStructGenerator_AttributeTypes() {
+	g, _ := generate.NewStructGenerator(schema.ReferenceSchema{
+		Name: "User",
+		Attributes: []*schema.Attribute{
+			{Name: "birthday", Type: schema.DateTimeType},
+			{Name: "photo", Type: schema.BinaryType},
+			{Name: "profileUrl", Type: schema.ReferenceType, ReferenceTypes: []string{"external"}},
+			{Name: "lastLogin", Type: schema.DateTimeType},
+		},
+	})
+	fmt.Print(g.Generate())
+	fmt.Println()
+
+	// Represent dateTime attributes by strings.
+	g.AttributeTypes(map[schema.Type]generate.CustomType{
+		schema.DateTimeType: {TypeName: "string"},
+	})
+	fmt.Print(g.Generate())
+
+	// Output:
+	// type User struct {
+	//     Birthday   time.Time
+	//     ExternalID string
+	//     ID         string
+	//     LastLogin  time.Time
+	//     Meta       UserMeta
+	//     Photo      []byte
+	//     ProfileUrl UserProfileUrl
+	//     Schemas    []string
+	// }
+	//
+	// // UserProfileUrl represents a reference of the "profileUrl" attribute.
+	// type UserProfileUrl string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserProfileUrl can reference.
+	// func (UserProfileUrl) ReferenceTypes() []string {
+	//     return []string{"external"}
+	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	//     ResourceType string
+	//     Created      time.Time
+	//     LastModified time.Time
+	//     Location     UserMetaLocation
+	//     Version      string
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	//     return []string{"uri"}
+	// }
+	//
+	// type User struct {
+	//     Birthday   string
+	//     ExternalID string
+	//     ID         string
+	//     LastLogin  string
+	//     Meta       UserMeta
+	//     Photo      []byte
+	//     ProfileUrl UserProfileUrl
+	//     Schemas    []string
+	// }
+	//
+	// // UserProfileUrl represents a reference of the "profileUrl" attribute.
+	// type UserProfileUrl string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserProfileUrl can reference.
+	// func (UserProfileUrl) ReferenceTypes() []string {
+	//     return []string{"external"}
+	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	//     ResourceType string
+	//     Created      string
+	//     LastModified string
+	//     Location     UserMetaLocation
+	//     Version      string
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	//     return []string{"uri"}
+	// }
+}
+
+func ExampleStructGenerator_Codecs() {
+	g, _ := generate.NewStructGenerator(schema.ReferenceSchema{
+		Name: "User",
+		Attributes: []*schema.Attribute{
+			{
+				Name:     "userName",
+				Type:     schema.StringType,
+				Required: true,
+			},
+			{
+				Name:        "emails",
+				Type:        schema.ComplexType,
+				MultiValued: true,
+				SubAttributes: []*schema.Attribute{
+					{
+						Name: "value",
+						Type: schema.StringType,
+					},
+				},
+			},
+		},
+	})
+	g.Codecs(true)
+	fmt.Print(g.Generate())
+
+	// Output:
+	// type User struct {
+	//     Emails     []UserEmail
+	//     ExternalID string
+	//     ID         string
+	//     Meta       UserMeta
+	//     Schemas    []string
+	//     UserName   string
+	// }
+	//
+	// // MarshalSCIM converts the User into a SCIM resource.
+	// func (v User) MarshalSCIM() (map[string]interface{}, error) {
+	//     resource := make(map[string]interface{})
+	//     if v.Emails != nil {
+	//         values := make([]interface{}, 0, len(v.Emails))
+	//         for _, value := range v.Emails {
+	//             element, err := value.MarshalSCIM()
+	//             if err != nil {
+	//                 return nil, err
+	//             }
+	//             values = append(values, element)
+	//         }
+	//         resource["emails"] = values
+	//     }
+	//     if v.ExternalID != "" {
+	//         resource["externalId"] = v.ExternalID
+	//     }
+	//     if v.ID != "" {
+	//         resource["id"] = v.ID
+	//     }
+	//     metaValue, err := v.Meta.MarshalSCIM()
+	//     if err != nil {
+	//         return nil, err
+	//     }
+	//     if len(metaValue) != 0 {
+	//         resource["meta"] = metaValue
+	//     }
+	//     if v.Schemas != nil {
+	//         values := make([]interface{}, 0, len(v.Schemas))
+	//         for _, value := range v.Schemas {
+	//             values = append(values, value)
+	//         }
+	//         resource["schemas"] = values
+	//     }
+	//     if v.UserName != "" {
+	//         resource["userName"] = v.UserName
+	//     }
+	//     return resource, nil
+	// }
+	//
+	// // UnmarshalSCIM fills the User with the given SCIM resource.
+	// func (v *User) UnmarshalSCIM(resource map[string]interface{}) error {
+	//     switch values := resource["emails"].(type) {
+	//     case nil:
+	//     case []interface{}:
+	//         v.Emails = make([]UserEmail, 0, len(values))
+	//         for _, value := range values {
+	//             switch value := value.(type) {
+	//             case map[string]interface{}:
+	//                 var element UserEmail
+	//                 if err := element.UnmarshalSCIM(value); err != nil {
+	//                     return err
+	//                 }
+	//                 v.Emails = append(v.Emails, element)
+	//             default:
+	//                 return fmt.Errorf("types of \"emails\" do not match: got %T, want map[string]interface{}", value)
+	//             }
+	//         }
+	//     default:
+	//         return fmt.Errorf("types of \"emails\" do not match: got %T, want []UserEmail", values)
+	//     }
+	//     switch value := resource["externalId"].(type) {
+	//     case nil:
+	//     case string:
+	//         v.ExternalID = value
+	//     default:
+	//         return fmt.Errorf("types of \"externalId\" do not match: got %T, want string", value)
+	//     }
+	//     switch value := resource["id"].(type) {
+	//     case nil:
+	//     case string:
+	//         v.ID = value
+	//     default:
+	//         return fmt.Errorf("types of \"id\" do not match: got %T, want string", value)
+	//     }
+	//     switch value := resource["meta"].(type) {
+	//     case nil:
+	//     case map[string]interface{}:
+	//         var element UserMeta
+	//         if err := element.UnmarshalSCIM(value); err != nil {
+	//             return err
+	//         }
+	//         v.Meta = element
+	//     default:
+	//         return fmt.Errorf("types of \"meta\" do not match: got %T, want map[string]interface{}", value)
+	//     }
+	//     switch values := resource["schemas"].(type) {
+	//     case nil:
+	//     case []interface{}:
+	//         v.Schemas = make([]string, 0, len(values))
+	//         for _, value := range values {
+	//             switch value := value.(type) {
+	//             case string:
+	//                 v.Schemas = append(v.Schemas, value)
+	//             default:
+	//                 return fmt.Errorf("types of \"schemas\" do not match: got %T, want string", value)
+	//             }
+	//         }
+	//     default:
+	//         return fmt.Errorf("types of \"schemas\" do not match: got %T, want []string", values)
+	//     }
+	//     switch value := resource["userName"].(type) {
+	//     case nil:
+	//     case string:
+	//         v.UserName = value
+	//     default:
+	//         return fmt.Errorf("types of \"userName\" do not match: got %T, want string", value)
+	//     }
+	//     return nil
+	// }
+	//
+	// type UserEmail struct {
+	//     Value string
+	// }
+	//
+	// // MarshalSCIM converts the UserEmail into a SCIM resource.
+	// func (v UserEmail) MarshalSCIM() (map[string]interface{}, error) {
+	//     resource := make(map[string]interface{})
+	//     if v.Value != "" {
+	//         resource["value"] = v.Value
+	//     }
+	//     return resource, nil
+	// }
+	//
+	// // UnmarshalSCIM fills the UserEmail with the given SCIM resource.
+	// func (v *UserEmail) UnmarshalSCIM(resource map[string]interface{}) error {
+	//     switch value := resource["value"].(type) {
+	//     case nil:
+	//     case string:
+	//         v.Value = value
+	//     default:
+	//         return fmt.Errorf("types of \"value\" do not match: got %T, want string", value)
+	//     }
+	//     return nil
+	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	//     ResourceType string
+	//     Created      time.Time
+	//     LastModified time.Time
+	//     Location     UserMetaLocation
+	//     Version      string
+	// }
+	//
+	// // MarshalSCIM converts the UserMeta into a SCIM resource.
+	// func (v UserMeta) MarshalSCIM() (map[string]interface{}, error) {
+	//     resource := make(map[string]interface{})
+	//     if v.ResourceType != "" {
+	//         resource["resourceType"] = v.ResourceType
+	//     }
+	//     if !v.Created.IsZero() {
+	//         resource["created"] = v.Created.Format(time.RFC3339)
+	//     }
+	//     if !v.LastModified.IsZero() {
+	//         resource["lastModified"] = v.LastModified.Format(time.RFC3339)
+	//     }
+	//     if v.Location != "" {
+	//         resource["location"] = string(v.Location)
+	//     }
+	//     if v.Version != "" {
+	//         resource["version"] = v.Version
+	//     }
+	//     return resource, nil
+	// }
+	//
+	// // UnmarshalSCIM fills the UserMeta with the given SCIM resource.
+	// func (v *UserMeta) UnmarshalSCIM(resource map[string]interface{}) error {
+	//     switch value := resource["resourceType"].(type) {
+	//     case nil:
+	//     case string:
+	//         v.ResourceType = value
+	//     default:
+	//         return fmt.Errorf("types of \"resourceType\" do not match: got %T, want string", value)
+	//     }
+	//     switch value := resource["created"].(type) {
+	//     case nil:
+	//     case string:
+	//         parsed, err := time.Parse(time.RFC3339, value)
+	//         if err != nil {
+	//             return fmt.Errorf("value of \"created\" is not a valid dateTime: %v", err)
+	//         }
+	//         v.Created = parsed
+	//     case time.Time:
+	//         v.Created = value
+	//     default:
+	//         return fmt.Errorf("types of \"created\" do not match: got %T, want string", value)
+	//     }
+	//     switch value := resource["lastModified"].(type) {
+	//     case nil:
+	//     case string:
+	//         parsed, err := time.Parse(time.RFC3339, value)
+	//         if err != nil {
+	//             return fmt.Errorf("value of \"lastModified\" is not a valid dateTime: %v", err)
+	//         }
+	//         v.LastModified = parsed
+	//     case time.Time:
+	//         v.LastModified = value
+	//     default:
+	//         return fmt.Errorf("types of \"lastModified\" do not match: got %T, want string", value)
+	//     }
+	//     switch value := resource["location"].(type) {
+	//     case nil:
+	//     case string:
+	//         v.Location = UserMetaLocation(value)
+	//     default:
+	//         return fmt.Errorf("types of \"location\" do not match: got %T, want string", value)
+	//     }
+	//     switch value := resource["version"].(type) {
+	//     case nil:
+	//     case string:
+	//         v.Version = value
+	//     default:
+	//         return fmt.Errorf("types of \"version\" do not match: got %T, want string", value)
+	//     }
+	//     return nil
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	//     return []string{"uri"}
+	// }
+}
+
+func ExampleStructGenerator_GenerateFile() {
+	g, _ := generate.NewStructGenerator(schema.ReferenceSchema{
+		Name:        "User",
+		Description: "User Account",
+		Attributes: []*schema.Attribute{
+			{
+				Name:     "userName",
+				Type:     schema.StringType,
+				Required: true,
+			},
+		},
+	})
+	g.Package("scim").CustomTypes([]generate.CustomType{
+		{
+			PkgPrefix: "uuid",
+			PkgPath:   "github.com/google/uuid",
+			AttrName:  "id",
+			TypeName:  "UUID",
+		},
+	})
+	file, _ := g.GenerateFile()
+	fmt.Print(string(file))
+
+	// Output:
+	// // Code generated by github.com/scim2/tools/generate. DO NOT EDIT.
+	//
+	// package scim
+	//
+	// import (
+	// 	"time"
+	//
+	// 	"github.com/google/uuid"
+	// )
+	//
+	// // User Account
+	// type User struct {
+	// 	ExternalID string
+	// 	ID         uuid.UUID
+	// 	Meta       UserMeta
+	// 	Schemas    []string
+	// 	UserName   string
+	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	// 	ResourceType string
+	// 	Created      time.Time
+	// 	LastModified time.Time
+	// 	Location     UserMetaLocation
+	// 	Version      string
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	// 	return []string{"uri"}
+	// }
+}
+
+func ExampleStructGenerator_Generate_canonicalValues() {
+	g, _ := generate.NewStructGenerator(schema.ReferenceSchema{
+		Name: "User",
+		Attributes: []*schema.Attribute{
+			{
+				Name:        "emails",
+				Type:        schema.ComplexType,
+				MultiValued: true,
+				SubAttributes: []*schema.Attribute{
+					{
+						Name: "value",
+						Type: schema.StringType,
+					},
+					{
+						Name:            "type",
+						Type:            schema.StringType,
+						CanonicalValues: []string{"work", "home", "other"},
+					},
+				},
+			},
+		},
+	})
+	fmt.Print(g.Generate())
+
+	// Output:
+	// type User struct {
+	//     Emails     []UserEmail
+	//     ExternalID string
+	//     ID         string
+	//     Meta       UserMeta
+	//     Schemas    []string
+	// }
+	//
+	// type UserEmail struct {
+	//     Value string
+	//     Type  UserEmailType
+	// }
+	//
+	// // UserEmailType represents the canonical values of the "type" attribute.
+	// type UserEmailType string
+	//
+	// const (
+	//     UserEmailTypeWork  UserEmailType = "work"
+	//     UserEmailTypeHome  UserEmailType = "home"
+	//     UserEmailTypeOther UserEmailType = "other"
+	// )
+	//
+	// // IsValid checks whether the UserEmailType is one of the canonical values.
+	// func (v UserEmailType) IsValid() bool {
+	//     switch v {
+	//     case UserEmailTypeWork, UserEmailTypeHome, UserEmailTypeOther:
+	//         return true
+	//     }
+	//     return false
+	// }
+	//
+	// // A complex attribute containing resource metadata.
+	// type UserMeta struct {
+	//     ResourceType string
+	//     Created      time.Time
+	//     LastModified time.Time
+	//     Location     UserMetaLocation
+	//     Version      string
+	// }
+	//
+	// // UserMetaLocation represents a reference of the "location" attribute.
+	// type UserMetaLocation string
+	//
+	// // ReferenceTypes returns the types of the resources that a UserMetaLocation can reference.
+	// func (UserMetaLocation) ReferenceTypes() []string {
+	//     return []string{"uri"}
+	// }
+}
@@ -0,0 +1,528 @@
+// Code generated by github.com/scim2/tools/generate. DO NOT EDIT.
+
+package resources
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+)
+
+// A device that is registered by a user. The description of this schema is long enough to be wrapped over multiple
+// lines, which makes sure that no words get lost when the comments are wrapped.
+type Device struct {
+	X2fa         *bool           `scim:"2fa" json:"2fa,omitempty"`
+	Addresses    []DeviceAddress `scim:"addresses,mV" json:"addresses,omitempty"`
+	BatteryLevel *float64        `scim:"batteryLevel" json:"batteryLevel,omitempty"`
+	ExternalID   *string         `scim:"externalId" json:"externalId,omitempty"`
+	Gateway      *netip.Addr     `scim:"gateway" json:"gateway,omitempty"`
+	ID           string          `scim:"id" json:"id,omitempty"`
+	IpAddresses  []netip.Addr    `scim:"ipAddresses,mV" json:"ipAddresses,omitempty"`
+	Kind         *DeviceKind     `scim:"kind" json:"kind,omitempty"`
+	Meta         *DeviceMeta     `scim:"meta" json:"meta,omitempty"`
+	Owner        *DeviceOwner    `scim:"owner" json:"owner,omitempty"`
+	PublicKey    []byte          `scim:"publicKey" json:"publicKey,omitempty"`
+	Registered   *time.Time      `scim:"registered" json:"registered,omitempty"`
+	Schemas      []string        `scim:"schemas,mV" json:"schemas,omitempty"`
+	SerialNumber string          `scim:"serialNumber" json:"serialNumber,omitempty"`
+}
+
+// MarshalSCIM converts the Device into a SCIM resource.
+func (v Device) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.X2fa != nil {
+		resource["2fa"] = *v.X2fa
+	}
+	if v.Addresses != nil {
+		values := make([]interface{}, 0, len(v.Addresses))
+		for _, value := range v.Addresses {
+			element, err := value.MarshalSCIM()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, element)
+		}
+		resource["addresses"] = values
+	}
+	if v.BatteryLevel != nil {
+		resource["batteryLevel"] = *v.BatteryLevel
+	}
+	if v.ExternalID != nil {
+		resource["externalId"] = *v.ExternalID
+	}
+	if v.Gateway != nil {
+		resource["gateway"] = *v.Gateway
+	}
+	if v.ID != "" {
+		resource["id"] = v.ID
+	}
+	if v.IpAddresses != nil {
+		values := make([]interface{}, 0, len(v.IpAddresses))
+		for _, value := range v.IpAddresses {
+			values = append(values, value)
+		}
+		resource["ipAddresses"] = values
+	}
+	if v.Kind != nil {
+		resource["kind"] = string(*v.Kind)
+	}
+	if v.Meta != nil {
+		metaValue, err := v.Meta.MarshalSCIM()
+		if err != nil {
+			return nil, err
+		}
+		if len(metaValue) == 0 {
+			resource["meta"] = nil
+		} else {
+			resource["meta"] = metaValue
+		}
+	}
+	if v.Owner != nil {
+		resource["owner"] = string(*v.Owner)
+	}
+	if len(v.PublicKey) != 0 {
+		resource["publicKey"] = base64.StdEncoding.EncodeToString(v.PublicKey)
+	}
+	if v.Registered != nil {
+		resource["registered"] = v.Registered.Format(time.RFC3339)
+	}
+	if v.Schemas != nil {
+		values := make([]interface{}, 0, len(v.Schemas))
+		for _, value := range v.Schemas {
+			values = append(values, value)
+		}
+		resource["schemas"] = values
+	}
+	if v.SerialNumber != "" {
+		resource["serialNumber"] = v.SerialNumber
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the Device with the given SCIM resource.
+func (v *Device) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["2fa"].(type) {
+	case nil:
+	case bool:
+		x2faValue := value
+		v.X2fa = &x2faValue
+	default:
+		return fmt.Errorf("types of \"2fa\" do not match: got %T, want bool", value)
+	}
+	switch values := resource["addresses"].(type) {
+	case nil:
+	case []interface{}:
+		v.Addresses = make([]DeviceAddress, 0, len(values))
+		for _, value := range values {
+			switch value := value.(type) {
+			case map[string]interface{}:
+				var element DeviceAddress
+				if err := element.UnmarshalSCIM(value); err != nil {
+					return err
+				}
+				v.Addresses = append(v.Addresses, element)
+			default:
+				return fmt.Errorf("types of \"addresses\" do not match: got %T, want map[string]interface{}", value)
+			}
+		}
+	default:
+		return fmt.Errorf("types of \"addresses\" do not match: got %T, want []DeviceAddress", values)
+	}
+	switch value := resource["batteryLevel"].(type) {
+	case nil:
+	case float64:
+		batteryLevelValue := value
+		v.BatteryLevel = &batteryLevelValue
+	case int:
+		batteryLevelValue := float64(value)
+		v.BatteryLevel = &batteryLevelValue
+	case int64:
+		batteryLevelValue := float64(value)
+		v.BatteryLevel = &batteryLevelValue
+	default:
+		return fmt.Errorf("types of \"batteryLevel\" do not match: got %T, want float64", value)
+	}
+	switch value := resource["externalId"].(type) {
+	case nil:
+	case string:
+		externalIDValue := value
+		v.ExternalID = &externalIDValue
+	default:
+		return fmt.Errorf("types of \"externalId\" do not match: got %T, want string", value)
+	}
+	switch value := resource["gateway"].(type) {
+	case nil:
+	case netip.Addr:
+		gatewayValue := value
+		v.Gateway = &gatewayValue
+	case string, float64, bool:
+		raw, _ := json.Marshal(value)
+		var element netip.Addr
+		if err := json.Unmarshal(raw, &element); err != nil {
+			return fmt.Errorf("value of \"gateway\" is not a valid netip.Addr: %v", err)
+		}
+		gatewayValue := element
+		v.Gateway = &gatewayValue
+	default:
+		return fmt.Errorf("types of \"gateway\" do not match: got %T, want netip.Addr", value)
+	}
+	switch value := resource["id"].(type) {
+	case nil:
+	case string:
+		v.ID = value
+	default:
+		return fmt.Errorf("types of \"id\" do not match: got %T, want string", value)
+	}
+	switch values := resource["ipAddresses"].(type) {
+	case nil:
+	case []interface{}:
+		v.IpAddresses = make([]netip.Addr, 0, len(values))
+		for _, value := range values {
+			switch value := value.(type) {
+			case netip.Addr:
+				v.IpAddresses = append(v.IpAddresses, value)
+			case string, float64, bool:
+				raw, _ := json.Marshal(value)
+				var element netip.Addr
+				if err := json.Unmarshal(raw, &element); err != nil {
+					return fmt.Errorf("value of \"ipAddresses\" is not a valid netip.Addr: %v", err)
+				}
+				v.IpAddresses = append(v.IpAddresses, element)
+			default:
+				return fmt.Errorf("types of \"ipAddresses\" do not match: got %T, want netip.Addr", value)
+			}
+		}
+	default:
+		return fmt.Errorf("types of \"ipAddresses\" do not match: got %T, want []netip.Addr", values)
+	}
+	switch value := resource["kind"].(type) {
+	case nil:
+	case string:
+		kindValue := DeviceKind(value)
+		v.Kind = &kindValue
+	default:
+		return fmt.Errorf("types of \"kind\" do not match: got %T, want string", value)
+	}
+	switch value := resource["meta"].(type) {
+	case nil:
+		if _, ok := resource["meta"]; ok {
+			v.Meta = new(DeviceMeta)
+		}
+	case map[string]interface{}:
+		var element DeviceMeta
+		if err := element.UnmarshalSCIM(value); err != nil {
+			return err
+		}
+		metaValue := element
+		v.Meta = &metaValue
+	default:
+		return fmt.Errorf("types of \"meta\" do not match: got %T, want map[string]interface{}", value)
+	}
+	switch value := resource["owner"].(type) {
+	case nil:
+	case string:
+		ownerValue := DeviceOwner(value)
+		v.Owner = &ownerValue
+	default:
+		return fmt.Errorf("types of \"owner\" do not match: got %T, want string", value)
+	}
+	switch value := resource["publicKey"].(type) {
+	case nil:
+	case string:
+		parsed, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return fmt.Errorf("value of \"publicKey\" is not a valid binary: %v", err)
+		}
+		v.PublicKey = parsed
+	case []byte:
+		v.PublicKey = value
+	default:
+		return fmt.Errorf("types of \"publicKey\" do not match: got %T, want string", value)
+	}
+	switch value := resource["registered"].(type) {
+	case nil:
+	case string:
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("value of \"registered\" is not a valid dateTime: %v", err)
+		}
+		registeredValue := parsed
+		v.Registered = &registeredValue
+	case time.Time:
+		registeredValue := value
+		v.Registered = &registeredValue
+	default:
+		return fmt.Errorf("types of \"registered\" do not match: got %T, want string", value)
+	}
+	switch values := resource["schemas"].(type) {
+	case nil:
+	case []interface{}:
+		v.Schemas = make([]string, 0, len(values))
+		for _, value := range values {
+			switch value := value.(type) {
+			case string:
+				v.Schemas = append(v.Schemas, value)
+			default:
+				return fmt.Errorf("types of \"schemas\" do not match: got %T, want string", value)
+			}
+		}
+	default:
+		return fmt.Errorf("types of \"schemas\" do not match: got %T, want []string", values)
+	}
+	switch value := resource["serialNumber"].(type) {
+	case nil:
+	case string:
+		v.SerialNumber = value
+	default:
+		return fmt.Errorf("types of \"serialNumber\" do not match: got %T, want string", value)
+	}
+	return nil
+}
+
+// Validate checks whether the Device conforms to the schema it was generated from.
+func (v Device) Validate() error {
+	for _, value := range v.Addresses {
+		if err := value.Validate(); err != nil {
+			return fmt.Errorf("addresses: %w", err)
+		}
+	}
+	if v.Kind != nil {
+		if !v.Kind.IsValid() {
+			return fmt.Errorf("value of \"kind\" is not a canonical value: %q", *v.Kind)
+		}
+	}
+	if v.Meta != nil {
+		if err := v.Meta.Validate(); err != nil {
+			return fmt.Errorf("meta: %w", err)
+		}
+	}
+	if len(v.Schemas) == 0 {
+		return errors.New("required attribute \"schemas\" is missing")
+	}
+	if v.SerialNumber == "" {
+		return errors.New("required attribute \"serialNumber\" is missing")
+	}
+	return nil
+}
+
+// DeviceKind represents the canonical values of the "kind" attribute.
+type DeviceKind string
+
+const (
+	DeviceKindPhone       DeviceKind = "phone"
+	DeviceKindLaptop      DeviceKind = "laptop"
+	DeviceKindSecuritykey DeviceKind = "security-key"
+)
+
+// IsValid checks whether the DeviceKind is one of the canonical values.
+func (v DeviceKind) IsValid() bool {
+	switch v {
+	case DeviceKindPhone, DeviceKindLaptop, DeviceKindSecuritykey:
+		return true
+	}
+	return false
+}
+
+// DeviceOwner represents a reference of the "owner" attribute.
+type DeviceOwner string
+
+// ReferenceTypes returns the types of the resources that a DeviceOwner can reference.
+func (DeviceOwner) ReferenceTypes() []string {
+	return []string{"User", "Group"}
+}
+
+// The network addresses of the device. Every address has a type, which is one of the canonical values, and a value.
+// This line is on its own line in the schema.
+type DeviceAddress struct {
+	Type     *DeviceAddressType `scim:"type" json:"type,omitempty"`
+	Value    string             `scim:"value" json:"value,omitempty"`
+	Priority *int               `scim:"priority" json:"priority,omitempty"`
+}
+
+// MarshalSCIM converts the DeviceAddress into a SCIM resource.
+func (v DeviceAddress) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.Type != nil {
+		resource["type"] = string(*v.Type)
+	}
+	if v.Value != "" {
+		resource["value"] = v.Value
+	}
+	if v.Priority != nil {
+		resource["priority"] = *v.Priority
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the DeviceAddress with the given SCIM resource.
+func (v *DeviceAddress) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["type"].(type) {
+	case nil:
+	case string:
+		typeValue := DeviceAddressType(value)
+		v.Type = &typeValue
+	default:
+		return fmt.Errorf("types of \"type\" do not match: got %T, want string", value)
+	}
+	switch value := resource["value"].(type) {
+	case nil:
+	case string:
+		v.Value = value
+	default:
+		return fmt.Errorf("types of \"value\" do not match: got %T, want string", value)
+	}
+	switch value := resource["priority"].(type) {
+	case nil:
+	case int:
+		priorityValue := value
+		v.Priority = &priorityValue
+	case int64:
+		priorityValue := int(value)
+		v.Priority = &priorityValue
+	case float64:
+		if value != float64(int(value)) {
+			return fmt.Errorf("value of \"priority\" is not a valid int: %v", value)
+		}
+		priorityValue := int(value)
+		v.Priority = &priorityValue
+	default:
+		return fmt.Errorf("types of \"priority\" do not match: got %T, want int", value)
+	}
+	return nil
+}
+
+// Validate checks whether the DeviceAddress conforms to the schema it was generated from.
+func (v DeviceAddress) Validate() error {
+	if v.Type != nil {
+		if !v.Type.IsValid() {
+			return fmt.Errorf("value of \"type\" is not a canonical value: %q", *v.Type)
+		}
+	}
+	if v.Value == "" {
+		return errors.New("required attribute \"value\" is missing")
+	}
+	return nil
+}
+
+// DeviceAddressType represents the canonical values of the "type" attribute.
+type DeviceAddressType string
+
+const (
+	DeviceAddressTypeIpv4 DeviceAddressType = "ipv4"
+	DeviceAddressTypeIpv6 DeviceAddressType = "ipv6"
+	DeviceAddressTypeMac  DeviceAddressType = "mac"
+)
+
+// IsValid checks whether the DeviceAddressType is one of the canonical values.
+func (v DeviceAddressType) IsValid() bool {
+	switch v {
+	case DeviceAddressTypeIpv4, DeviceAddressTypeIpv6, DeviceAddressTypeMac:
+		return true
+	}
+	return false
+}
+
+// A complex attribute containing resource metadata.
+type DeviceMeta struct {
+	ResourceType *string             `scim:"resourceType" json:"resourceType,omitempty"`
+	Created      *time.Time          `scim:"created" json:"created,omitempty"`
+	LastModified *time.Time          `scim:"lastModified" json:"lastModified,omitempty"`
+	Location     *DeviceMetaLocation `scim:"location" json:"location,omitempty"`
+	Version      *string             `scim:"version" json:"version,omitempty"`
+}
+
+// MarshalSCIM converts the DeviceMeta into a SCIM resource.
+func (v DeviceMeta) MarshalSCIM() (map[string]interface{}, error) {
+	resource := make(map[string]interface{})
+	if v.ResourceType != nil {
+		resource["resourceType"] = *v.ResourceType
+	}
+	if v.Created != nil {
+		resource["created"] = v.Created.Format(time.RFC3339)
+	}
+	if v.LastModified != nil {
+		resource["lastModified"] = v.LastModified.Format(time.RFC3339)
+	}
+	if v.Location != nil {
+		resource["location"] = string(*v.Location)
+	}
+	if v.Version != nil {
+		resource["version"] = *v.Version
+	}
+	return resource, nil
+}
+
+// UnmarshalSCIM fills the DeviceMeta with the given SCIM resource.
+func (v *DeviceMeta) UnmarshalSCIM(resource map[string]interface{}) error {
+	switch value := resource["resourceType"].(type) {
+	case nil:
+	case string:
+		resourceTypeValue := value
+		v.ResourceType = &resourceTypeValue
+	default:
+		return fmt.Errorf("types of \"resourceType\" do not match: got %T, want string", value)
+	}
+	switch value := resource["created"].(type) {
+	case nil:
+	case string:
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("value of \"created\" is not a valid dateTime: %v", err)
+		}
+		createdValue := parsed
+		v.Created = &createdValue
+	case time.Time:
+		createdValue := value
+		v.Created = &createdValue
+	default:
+		return fmt.Errorf("types of \"created\" do not match: got %T, want string", value)
+	}
+	switch value := resource["lastModified"].(type) {
+	case nil:
+	case string:
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("value of \"lastModified\" is not a valid dateTime: %v", err)
+		}
+		lastModifiedValue := parsed
+		v.LastModified = &lastModifiedValue
+	case time.Time:
+		lastModifiedValue := value
+		v.LastModified = &lastModifiedValue
+	default:
+		return fmt.Errorf("types of \"lastModified\" do not match: got %T, want string", value)
+	}
+	switch value := resource["location"].(type) {
+	case nil:
+	case string:
+		locationValue := DeviceMetaLocation(value)
+		v.Location = &locationValue
+	default:
+		return fmt.Errorf("types of \"location\" do not match: got %T, want string", value)
+	}
+	switch value := resource["version"].(type) {
+	case nil:
+	case string:
+		versionValue := value
+		v.Version = &versionValue
+	default:
+		return fmt.Errorf("types of \"version\" do not match: got %T, want string", value)
+	}
+	return nil
+}
+
+// Validate checks whether the DeviceMeta conforms to the schema it was generated from.
+func (v DeviceMeta) Validate() error {
+	return nil
+}
+
+// DeviceMetaLocation represents a reference of the "location" attribute.
+type DeviceMetaLocation string
+
+// ReferenceTypes returns the types of the resources that a DeviceMetaLocation can reference.
+func (DeviceMetaLocation) ReferenceTypes() []string {
+	return []string{"uri"}
+}
@@ -0,0 +1,71 @@
+// Code generated by github.com/scim2/tools/generate. DO NOT EDIT.
+
+/**
+ * A device that is registered by a user. The description of this schema is long enough to be wrapped over multiple
+ * lines, which makes sure that no words get lost when the comments are wrapped.
+ */
+export interface Device {
+    /** Whether the device is used as a second factor. */
+    "2fa"?: boolean;
+    /**
+     * The network addresses of the device. Every address has a type, which is one of the canonical values, and a value.
+     * This line is on its own line in the schema.
+     */
+    addresses?: DeviceAddress[];
+    /** The battery level of the device, between 0 and 1. */
+    readonly batteryLevel?: number;
+    /** A String that is an identifier for the resource as defined by the provisioning client. */
+    externalId?: string;
+    /** The IP address of the default gateway of the device. */
+    gateway?: string;
+    /** A unique identifier for a SCIM resource as defined by the service provider. */
+    readonly id: string;
+    /** The IP addresses of the device. */
+    ipAddresses?: string[];
+    /** The kind of the device. */
+    kind?: DeviceKind;
+    /** A complex attribute containing resource metadata. */
+    readonly meta?: DeviceMeta;
+    /** The URI of the user or group that owns the device. */
+    owner?: string;
+    /** The DER encoded public key of the device. */
+    publicKey?: string;
+    /** The date and time at which the device was registered. */
+    readonly registered?: string;
+    schemas: string[];
+    /** The serial number of the device. */
+    serialNumber: string;
+}
+
+/**
+ * The network addresses of the device. Every address has a type, which is one of the canonical values, and a value.
+ * This line is on its own line in the schema.
+ */
+export interface DeviceAddress {
+    /** The type of the address. */
+    type?: DeviceAddressType;
+    /** The address. */
+    value: string;
+    /** The priority of the address, lower values first. */
+    priority?: number;
+}
+
+/** The canonical values of the "type" attribute. */
+export type DeviceAddressType = "ipv4" | "ipv6" | "mac";
+
+/** The canonical values of the "kind" attribute. */
+export type DeviceKind = "phone" | "laptop" | "security-key";
+
+/** A complex attribute containing resource metadata. */
+export interface DeviceMeta {
+    /** The name of the resource type of the resource. */
+    readonly resourceType?: string;
+    /** The DateTime that the resource was added to the service provider. */
+    readonly created?: string;
+    /** The most recent DateTime that the details of this resource were updated at the service provider. */
+    readonly lastModified?: string;
+    /** The URI of the resource being returned. */
+    readonly location?: string;
+    /** The version of the resource being returned. */
+    readonly version?: string;
+}
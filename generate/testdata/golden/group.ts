@@ -0,0 +1,43 @@
+// Code generated by github.com/scim2/tools/generate. DO NOT EDIT.
+
+/** Group */
+export interface Group {
+    /** A human-readable name for the Group. REQUIRED. */
+    displayName?: string;
+    /** A String that is an identifier for the resource as defined by the provisioning client. */
+    externalId?: string;
+    /** A unique identifier for a SCIM resource as defined by the service provider. */
+    readonly id: string;
+    /** A list of members of the Group. */
+    members?: GroupMember[];
+    /** A complex attribute containing resource metadata. */
+    readonly meta?: GroupMeta;
+    schemas: string[];
+}
+
+/** A list of members of the Group. */
+export interface GroupMember {
+    /** Identifier of the member of this Group. */
+    value?: string;
+    /** The URI corresponding to a SCIM resource that is a member of this Group. */
+    $ref?: string;
+    /** A label indicating the type of resource, e.g., 'User' or 'Group'. */
+    type?: GroupMemberType;
+}
+
+/** The canonical values of the "type" attribute. */
+export type GroupMemberType = "User" | "Group";
+
+/** A complex attribute containing resource metadata. */
+export interface GroupMeta {
+    /** The name of the resource type of the resource. */
+    readonly resourceType?: string;
+    /** The DateTime that the resource was added to the service provider. */
+    readonly created?: string;
+    /** The most recent DateTime that the details of this resource were updated at the service provider. */
+    readonly lastModified?: string;
+    /** The URI of the resource being returned. */
+    readonly location?: string;
+    /** The version of the resource being returned. */
+    readonly version?: string;
+}
@@ -0,0 +1,91 @@
+// Code generated by github.com/scim2/tools/generate. DO NOT EDIT.
+
+/** User Account */
+export interface User {
+    active?: boolean;
+    displayName?: string;
+    emails?: UserEmail[];
+    /** A String that is an identifier for the resource as defined by the provisioning client. */
+    externalId?: string;
+    readonly groups?: UserGroup[];
+    /** A unique identifier for a SCIM resource as defined by the service provider. */
+    readonly id: string;
+    readonly lastLogin?: string;
+    readonly loginCount?: number;
+    /** A complex attribute containing resource metadata. */
+    readonly meta?: UserMeta;
+    /** The components of the user's real name. */
+    name?: UserName;
+    nickNames?: string[];
+    password?: string;
+    phoneNumbers?: UserPhoneNumber[];
+    profileUrl?: string;
+    schemas: string[];
+    score?: number;
+    /** Unique identifier for the User, typically used by the user to directly authenticate to the service provider. */
+    userName: string;
+    x509Certificates?: UserX509Certificate[];
+    "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"?: EnterpriseUserExtension;
+}
+
+export interface UserEmail {
+    value?: string;
+    display?: string;
+    type?: UserEmailType;
+    primary?: boolean;
+}
+
+/** The canonical values of the "type" attribute. */
+export type UserEmailType = "work" | "home" | "other";
+
+export interface UserGroup {
+    readonly value?: string;
+    readonly $ref?: string;
+    readonly display?: string;
+}
+
+/** A complex attribute containing resource metadata. */
+export interface UserMeta {
+    /** The name of the resource type of the resource. */
+    readonly resourceType?: string;
+    /** The DateTime that the resource was added to the service provider. */
+    readonly created?: string;
+    /** The most recent DateTime that the details of this resource were updated at the service provider. */
+    readonly lastModified?: string;
+    /** The URI of the resource being returned. */
+    readonly location?: string;
+    /** The version of the resource being returned. */
+    readonly version?: string;
+}
+
+/** The components of the user's real name. */
+export interface UserName {
+    formatted?: string;
+    familyName?: string;
+    givenName?: string;
+}
+
+export interface UserPhoneNumber {
+    value?: string;
+    type?: UserPhoneNumberType;
+    primary?: boolean;
+}
+
+/** The canonical values of the "type" attribute. */
+export type UserPhoneNumberType = "work" | "home" | "mobile" | "fax" | "pager" | "other";
+
+export interface UserX509Certificate {
+    value?: string;
+}
+
+/** Enterprise User */
+export interface EnterpriseUserExtension {
+    employeeNumber?: string;
+    manager?: EnterpriseUserExtensionManager;
+}
+
+export interface EnterpriseUserExtensionManager {
+    value?: string;
+    $ref?: string;
+    readonly displayName?: string;
+}
@@ -0,0 +1,146 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/scim2/tools/schema"
+)
+
+// TypeScriptGenerator generates TypeScript type definitions of the resource described in a schema. The interfaces and
+// types have the same names as the structs and types of the StructGenerator.
+type TypeScriptGenerator struct {
+	g StructGenerator
+	w *genWriter
+}
+
+// NewTypeScriptGenerator returns a generator of the TypeScript definitions of the resource described in the given
+// schema. The extensions become properties keyed by their URNs.
+func NewTypeScriptGenerator(s schema.ReferenceSchema, extensions ...schema.ReferenceSchema) (TypeScriptGenerator, error) {
+	g, err := NewStructGenerator(s, extensions...)
+	if err != nil {
+		return TypeScriptGenerator{}, err
+	}
+	return TypeScriptGenerator{g: g, w: newGenWriter(&bytes.Buffer{})}, nil
+}
+
+// Generate creates a buffer with the TypeScript definitions of the resource described in the given schema. Attributes
+// that are not required are optional properties, read-only attributes are readonly properties and attributes with
+// canonical values get a union type of string literals. The names are not checked, use GenerateFile to detect names
+// that collide or that are not valid identifiers.
+func (g *TypeScriptGenerator) Generate() *bytes.Buffer {
+	g.w = newGenWriter(&bytes.Buffer{})
+	g.generateInterface(g.g.resourceName(), g.g.s.Description, g.g.s.Attributes, true)
+	for _, e := range g.g.e {
+		g.w.n()
+		g.generateInterface(g.g.extensionName(e)+"Extension", e.Description, e.Attributes, false)
+	}
+	return g.w.writer.(*bytes.Buffer)
+}
+
+// GenerateFile creates a TypeScript file of the resource described in the given schema, with a header that marks it as
+// generated. It returns an error if names collide or are not valid identifiers, like the struct generator.
+func (g *TypeScriptGenerator) GenerateFile() ([]byte, error) {
+	if err := g.g.checkNames(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by github.com/scim2/tools/generate. DO NOT EDIT.\n\n")
+	buf.Write(g.Generate().Bytes())
+	return buf.Bytes(), nil
+}
+
+func (g *TypeScriptGenerator) generateInterface(name, desc string, attrs []*schema.Attribute, core bool) {
+	w := g.w
+
+	g.generateDoc(w, desc)
+	w.lnf("export interface %s {", name)
+	for _, attr := range attrs {
+		g.generateDoc(w.in(4), attr.Description)
+		var readonly, optional string
+		if attr.Mutability == schema.ReadOnly {
+			readonly = "readonly "
+		}
+		if !attr.Required {
+			optional = "?"
+		}
+		w.in(4).lnf("%s%s%s: %s;", readonly, propertyName(attr.Name), optional, g.propertyType(name, attr))
+	}
+	if core {
+		for _, e := range g.g.e {
+			w.in(4).lnf("%s?: %s;", propertyName(e.ID), g.g.extensionName(e)+"Extension")
+		}
+	}
+	w.ln("}")
+
+	for _, attr := range attrs {
+		f := g.g.field(name, attr)
+		switch {
+		case attr.Type == schema.ComplexType:
+			w.n()
+			g.generateInterface(f.typ, attr.Description, attr.SubAttributes, false)
+		case isCanonical(attr):
+			values := make([]string, len(attr.CanonicalValues))
+			for i, v := range attr.CanonicalValues {
+				values[i] = fmt.Sprintf("%q", v)
+			}
+			w.n()
+			w.lnf("/** The canonical values of the %q attribute. */", attr.Name)
+			w.lnf("export type %s = %s;", f.typ, strings.Join(values, " | "))
+		}
+	}
+}
+
+// propertyType returns the TypeScript type of the given attribute within the interface with the given name. The
+// dateTime, binary and reference attributes are represented by their string values.
+func (g *TypeScriptGenerator) propertyType(name string, attr *schema.Attribute) string {
+	var typ string
+	switch attr.Type {
+	case schema.DecimalType, schema.IntegerType:
+		typ = "number"
+	case schema.BooleanType:
+		typ = "boolean"
+	case schema.ComplexType:
+		typ = g.g.field(name, attr).typ
+	default:
+		typ = "string"
+		if isCanonical(attr) {
+			typ = g.g.field(name, attr).typ
+		}
+	}
+	if attr.MultiValued {
+		typ += "[]"
+	}
+	return typ
+}
+
+// generateDoc generates a JSDoc comment of the given description.
+func (g *TypeScriptGenerator) generateDoc(w *genWriter, desc string) {
+	desc = strings.Join(strings.Fields(desc), " ") // descriptions are often hard wrapped
+	if desc == "" {
+		return
+	}
+	desc = strings.Replace(desc, "*/", "*\\/", -1)
+	lines := strings.Split(strings.TrimSpace(wrap(desc, 113)), "\n") // 120 - " * " - indentation
+	if len(lines) == 1 {
+		w.lnf("/** %s */", lines[0])
+		return
+	}
+	w.ln("/**")
+	for _, line := range lines {
+		w.lnf(" * %s", line)
+	}
+	w.ln(" */")
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// propertyName returns the name of the property of an attribute, quoted if it is not a valid identifier.
+func propertyName(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
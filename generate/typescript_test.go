@@ -0,0 +1,131 @@
+package generate_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/scim2/tools/generate"
+	"github.com/scim2/tools/schema"
+)
+
+func TestTypeScriptGenerator(t *testing.T) {
+	if _, err := generate.NewTypeScriptGenerator(schema.ReferenceSchema{}); err == nil {
+		t.Error("error expected, got none")
+	}
+}
+
+func TestTypeScriptGenerator_GenerateFile(t *testing.T) {
+	g, err := generate.NewTypeScriptGenerator(schema.ReferenceSchema{
+		Name:       "User",
+		Attributes: []*schema.Attribute{{Name: "ref"}, {Name: "$ref"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `attribute "$ref" and attribute "ref" both have the field name User.Ref`
+	if _, err := g.GenerateFile(); err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func ExampleTypeScriptGenerator_Generate() {
+	g, _ := generate.NewTypeScriptGenerator(
+		schema.ReferenceSchema{
+			Name:        "User",
+			Description: "User Account",
+			Attributes: []*schema.Attribute{
+				{
+					Name:        "userName",
+					Description: "Unique identifier for the User.",
+					Required:    true,
+				},
+				{
+					Name:        "active",
+					Type:        schema.BooleanType,
+					Description: "A Boolean value indicating the User's administrative status.",
+				},
+				{
+					Name:        "emails",
+					Type:        schema.ComplexType,
+					MultiValued: true,
+					SubAttributes: []*schema.Attribute{
+						{Name: "value"},
+						{Name: "type", CanonicalValues: []string{"work", "home", "other"}},
+						{Name: "primary", Type: schema.BooleanType},
+					},
+				},
+				{
+					Name:        "groups",
+					Type:        schema.ComplexType,
+					MultiValued: true,
+					Mutability:  schema.ReadOnly,
+					SubAttributes: []*schema.Attribute{
+						{Name: "value", Mutability: schema.ReadOnly},
+						{Name: "$ref", Type: schema.ReferenceType, Mutability: schema.ReadOnly},
+					},
+				},
+			},
+		},
+		schema.ReferenceSchema{
+			ID:   "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User",
+			Name: "Enterprise User",
+			Attributes: []*schema.Attribute{
+				{Name: "employeeNumber"},
+				{Name: "costCenter", Type: schema.IntegerType},
+			},
+		},
+	)
+	fmt.Print(g.Generate())
+
+	// Output:
+	// /** User Account */
+	// export interface User {
+	//     /** A Boolean value indicating the User's administrative status. */
+	//     active?: boolean;
+	//     emails?: UserEmail[];
+	//     /** A String that is an identifier for the resource as defined by the provisioning client. */
+	//     externalId?: string;
+	//     readonly groups?: UserGroup[];
+	//     /** A unique identifier for a SCIM resource as defined by the service provider. */
+	//     readonly id: string;
+	//     /** A complex attribute containing resource metadata. */
+	//     readonly meta?: UserMeta;
+	//     schemas: string[];
+	//     /** Unique identifier for the User. */
+	//     userName: string;
+	//     "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"?: EnterpriseUserExtension;
+	// }
+	//
+	// export interface UserEmail {
+	//     value?: string;
+	//     type?: UserEmailType;
+	//     primary?: boolean;
+	// }
+	//
+	// /** The canonical values of the "type" attribute. */
+	// export type UserEmailType = "work" | "home" | "other";
+	//
+	// export interface UserGroup {
+	//     readonly value?: string;
+	//     readonly $ref?: string;
+	// }
+	//
+	// /** A complex attribute containing resource metadata. */
+	// export interface UserMeta {
+	//     /** The name of the resource type of the resource. */
+	//     readonly resourceType?: string;
+	//     /** The DateTime that the resource was added to the service provider. */
+	//     readonly created?: string;
+	//     /** The most recent DateTime that the details of this resource were updated at the service provider. */
+	//     readonly lastModified?: string;
+	//     /** The URI of the resource being returned. */
+	//     readonly location?: string;
+	//     /** The version of the resource being returned. */
+	//     readonly version?: string;
+	// }
+	//
+	// export interface EnterpriseUserExtension {
+	//     costCenter?: number;
+	//     employeeNumber?: string;
+	// }
+}
@@ -14,16 +14,6 @@ func keepAlpha(s string) string {
 	return reg.ReplaceAllString(s, "")
 }
 
-func singular(s string) string {
-	if strings.HasSuffix(s, "ses") {
-		return strings.TrimSuffix(s, "es")
-	}
-	if strings.HasSuffix(s, "s") {
-		return strings.TrimSuffix(s, "s")
-	}
-	return s
-}
-
 // cap capitalizes every word and removes the spaces.
 func cap(s string) string {
 	if s == "id" {
@@ -41,28 +31,29 @@ func comment(s string) string {
 	return "// " + strings.Replace(s, "\n", "\n// ", -1)
 }
 
-// wrap splits the given strings in lines of maximum lw characters.
+// wrap splits the given strings in lines of maximum lw characters. Words that are longer than lw get a line of their
+// own.
 func wrap(s string, lw int) string {
-	var wrapped string
+	var lines []string
 	for _, line := range strings.Split(s, "\n") {
 		if len(line) <= lw {
-			wrapped += line
+			lines = append(lines, line)
 			continue
 		}
 
 		var wrappedLine string
 		for _, word := range strings.Split(line, " ") {
-			if len(wrappedLine)+len(word) <= lw {
-				if wrappedLine != "" {
-					wrappedLine += " "
-				}
-				wrappedLine += word
-			} else {
-				wrapped += "\n" + wrappedLine
-				wrappedLine = ""
+			switch {
+			case wrappedLine == "":
+				wrappedLine = word
+			case len(wrappedLine)+1+len(word) <= lw:
+				wrappedLine += " " + word
+			default:
+				lines = append(lines, wrappedLine)
+				wrappedLine = word
 			}
 		}
-		wrapped += "\n" + wrappedLine
+		lines = append(lines, wrappedLine)
 	}
-	return wrapped
+	return strings.Join(lines, "\n")
 }
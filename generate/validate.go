@@ -0,0 +1,280 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scim2/tools/schema"
+)
+
+// generateValidate generates the Validate method of the struct with the given name.
+func (g *StructGenerator) generateValidate(name string, attrs []*schema.Attribute, core bool) {
+	w := g.w
+
+	fields := make([]field, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = append(fields, g.field(name, attr))
+	}
+	if core {
+		for _, e := range g.e {
+			extensionName := g.extensionName(e)
+			fields = append(fields, field{
+				attr: &schema.Attribute{
+					Name:          e.ID,
+					Type:          schema.ComplexType,
+					SubAttributes: e.Attributes,
+				},
+				name: extensionName,
+				typ:  extensionName + "Extension",
+			})
+		}
+	}
+
+	w.n()
+	w.lnf("// Validate checks whether the %s conforms to the schema it was generated from.", name)
+	w.lnf("func (v %s) Validate() error {", name)
+	for _, f := range fields {
+		g.generateValidateField(f)
+	}
+	w.in(4).ln("return nil")
+	w.ln("}")
+}
+
+func (g *StructGenerator) generateValidateField(f field) {
+	w := g.w.in(4)
+
+	// Read-only attributes are assigned by the service provider, these are missing in the body of a request.
+	if f.attr.Required && f.attr.Mutability != schema.ReadOnly {
+		g.generateRequired(w, f)
+	}
+
+	if !f.attr.MultiValued {
+		if !f.isValidated() {
+			return
+		}
+		value := "v." + f.name
+		if f.ptr {
+			w.lnf("if v.%s != nil {", f.name)
+			g.generateValidateValue(w.in(4), f, value)
+			w.ln("}")
+			return
+		}
+		if f.isComplex() && !f.attr.Required {
+			// Empty structs represent absent attributes, these do not have to be valid.
+			if notEmpty, ok := g.emptyCheck(f, value, false); ok {
+				w.lnf("if %s {", notEmpty)
+				g.generateValidateValue(w.in(4), f, value)
+				w.ln("}")
+				return
+			}
+		}
+		g.generateValidateValue(w, f, value)
+		return
+	}
+
+	// Multi valued attributes are checked within a single loop.
+	unique, primary := g.uniqueValue(f), g.primaryValue(f)
+	if !f.isValidated() && unique == nil && primary == nil {
+		return
+	}
+	seen, primaries := lowerFirst(f.name)+"Seen", lowerFirst(f.name)+"Primary"
+	if unique != nil {
+		w.lnf("%s := make(map[%s]bool, len(v.%s))", seen, unique.typ, f.name)
+	}
+	if primary != nil {
+		w.lnf("var %s bool", primaries)
+	}
+	w.lnf("for _, value := range v.%s {", f.name)
+	g.generateValidateValue(w.in(4), f, "value")
+	if unique != nil {
+		value := "value"
+		if f.isComplex() {
+			value += "." + unique.name
+		}
+		wu := w.in(4)
+		if unique.ptr {
+			wu.lnf("if %s != nil {", value)
+			wu, value = wu.in(4), "*"+value
+		}
+		wu.lnf("if %s[%s] {", seen, value)
+		wu.in(4).lnf("return fmt.Errorf(%q, %s)", fmt.Sprintf("duplicate value of %q: %%v", f.attr.Name), value)
+		wu.ln("}")
+		wu.lnf("%s[%s] = true", seen, value)
+		if unique.ptr {
+			w.in(4).ln("}")
+		}
+	}
+	if primary != nil {
+		if primary.ptr {
+			w.in(4).lnf("if value.%[1]s != nil && *value.%[1]s {", primary.name)
+		} else {
+			w.in(4).lnf("if value.%s {", primary.name)
+		}
+		w.in(8).lnf("if %s {", primaries)
+		w.in(12).lnf("return errors.New(%q)", fmt.Sprintf("multiple primary values of %q", f.attr.Name))
+		w.in(8).ln("}")
+		w.in(8).lnf("%s = true", primaries)
+		w.in(4).ln("}")
+		g.addImport("errors", "errors")
+	}
+	w.ln("}")
+}
+
+// generateRequired generates a check that returns an error if the required attribute of the given field is missing.
+// An attribute is missing if it would not be present in the marshalled resource.
+func (g *StructGenerator) generateRequired(w *genWriter, f field) {
+	switch {
+	case f.attr.MultiValued:
+		w.lnf("if len(v.%s) == 0 {", f.name)
+	case f.ptr:
+		w.lnf("if v.%s == nil {", f.name)
+	case f.kind == "time.Time":
+		w.lnf("if v.%s.IsZero() {", f.name)
+	case f.kind == "[]byte":
+		w.lnf("if len(v.%s) == 0 {", f.name)
+	case f.isSimple() && f.kind == "bool":
+		w.lnf("if !v.%s {", f.name)
+	case f.isSimple():
+		w.lnf("if v.%s == %s {", f.name, zeroValues[f.kind])
+	case f.isComplex() && !f.custom:
+		empty, ok := g.emptyCheck(f, "v."+f.name, true)
+		if !ok {
+			return
+		}
+		w.lnf("if %s {", empty)
+	default:
+		// Custom types can not be checked.
+		return
+	}
+	w.in(4).lnf("return errors.New(%q)", fmt.Sprintf("required attribute %q is missing", f.attr.Name))
+	w.ln("}")
+	g.addImport("errors", "errors")
+}
+
+// generateValidateValue generates the checks of a single (non nil) value of the given field.
+func (g *StructGenerator) generateValidateValue(w *genWriter, f field, value string) {
+	switch {
+	case f.custom:
+	case f.isComplex():
+		w.lnf("if err := %s.Validate(); err != nil {", value)
+		w.in(4).lnf("return fmt.Errorf(%q, err)", fmt.Sprintf("%s: %%w", f.attr.Name))
+		w.ln("}")
+		g.addImport("fmt", "fmt")
+	case isCanonical(f.attr):
+		if f.attr.MultiValued || f.ptr {
+			w.lnf("if !%s.IsValid() {", value)
+		} else {
+			w.lnf("if %s != \"\" && !%s.IsValid() {", value, value)
+		}
+		if f.ptr {
+			value = "*" + value
+		}
+		w.in(4).lnf("return fmt.Errorf(%q, %s)", fmt.Sprintf("value of %q is not a canonical value: %%q", f.attr.Name), value)
+		w.ln("}")
+		g.addImport("fmt", "fmt")
+	}
+}
+
+// isValidated checks whether generateValidateValue generates checks for the values of the field.
+func (f field) isValidated() bool {
+	return !f.custom && (f.isComplex() || isCanonical(f.attr))
+}
+
+// uniqueValue returns the field of which the values have to be unique within the given multi valued field. This is
+// either the "value" sub attribute of a complex attribute or the attribute itself. It returns nil if the uniqueness
+// can not be checked.
+func (g *StructGenerator) uniqueValue(f field) *field {
+	if f.custom {
+		return nil
+	}
+	if !f.isComplex() {
+		if !isUnique(f.attr) {
+			return nil
+		}
+		return &f
+	}
+	for _, sub := range f.attr.SubAttributes {
+		if strings.EqualFold(sub.Name, "value") && isUnique(sub) && !sub.MultiValued {
+			if v := g.field(f.typ, sub); v.isSimple() {
+				return &v
+			}
+		}
+	}
+	return nil
+}
+
+// primaryValue returns the "primary" sub attribute of the given multi valued complex field. It returns nil if there is
+// no such boolean sub attribute.
+func (g *StructGenerator) primaryValue(f field) *field {
+	if f.custom || !f.isComplex() {
+		return nil
+	}
+	for _, sub := range f.attr.SubAttributes {
+		if strings.EqualFold(sub.Name, "primary") && !sub.MultiValued {
+			if v := g.field(f.typ, sub); v.kind == "bool" {
+				return &v
+			}
+		}
+	}
+	return nil
+}
+
+// emptyCheck returns an expression that checks whether the given value of the complex field is empty, or not empty if
+// empty is false. It returns false if the sub attributes contain custom types, which can not be checked.
+func (g *StructGenerator) emptyCheck(f field, value string, empty bool) (string, bool) {
+	if g.isComparable(f.attr.SubAttributes) {
+		if empty {
+			return fmt.Sprintf("%s == (%s{})", value, f.typ), true
+		}
+		return fmt.Sprintf("%s != (%s{})", value, f.typ), true
+	}
+
+	eq, op := "==", " && "
+	if !empty {
+		eq, op = "!=", " || "
+	}
+	checks := make([]string, 0, len(f.attr.SubAttributes))
+	for _, attr := range f.attr.SubAttributes {
+		sub := g.field(f.typ, attr)
+		name := value + "." + sub.name
+		switch {
+		case sub.custom:
+			return "", false
+		case attr.MultiValued || sub.kind == "[]byte":
+			checks = append(checks, fmt.Sprintf("len(%s) %s 0", name, eq))
+		case sub.kind == "time.Time" && empty:
+			checks = append(checks, fmt.Sprintf("%s.IsZero()", name))
+		case sub.kind == "time.Time":
+			checks = append(checks, fmt.Sprintf("!%s.IsZero()", name))
+		case sub.ptr:
+			checks = append(checks, fmt.Sprintf("%s %s nil", name, eq))
+		case sub.isComplex():
+			check, ok := g.emptyCheck(sub, name, empty)
+			if !ok {
+				return "", false
+			}
+			checks = append(checks, fmt.Sprintf("(%s)", check))
+		default:
+			checks = append(checks, fmt.Sprintf("%s %s %s", name, eq, zeroValues[sub.kind]))
+		}
+	}
+	return strings.Join(checks, op), true
+}
+
+// isComparable checks whether the struct generated from the given attributes can be compared with ==.
+func (g *StructGenerator) isComparable(attrs []*schema.Attribute) bool {
+	for _, attr := range attrs {
+		if f := g.field("", attr); f.custom || f.kind == "[]byte" || attr.MultiValued {
+			return false
+		}
+		if attr.Type == schema.ComplexType && !g.isComparable(attr.SubAttributes) {
+			return false
+		}
+	}
+	return true
+}
+
+// isUnique checks whether the values of the attribute have to be unique.
+func isUnique(attr *schema.Attribute) bool {
+	return attr.Uniqueness == schema.Server || attr.Uniqueness == schema.Global
+}
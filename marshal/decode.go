@@ -1,143 +1,351 @@
 package marshal
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 )
 
-var (
-	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
-	mapType         = reflect.TypeOf(map[string]interface{}{})
-	sliceType       = reflect.TypeOf([]interface{}{})
-)
+var unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+
+// Decoder fills structs with resource maps.
+type Decoder struct {
+	strict     bool
+	extensions []string
+}
+
+// NewDecoder returns a new Decoder.
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
 
+// Strict makes the decoder return an error if the data contains attributes that do not correspond with a field of
+// the struct it gets decoded into. The given extension URNs are allowed to be present without a corresponding field.
+func (d *Decoder) Strict(extensions ...string) *Decoder {
+	d.strict = true
+	d.extensions = extensions
+	return d
+}
+
+// Unmarshal fills the struct that the given value points to with the given data.
 func Unmarshal(data map[string]interface{}, value interface{}) error {
+	return NewDecoder().Unmarshal(data, value)
+}
+
+// Unmarshal fills the value that the given value points to with the given data. The value can be a struct, a map
+// with string keys or an empty interface.
+func (d *Decoder) Unmarshal(data map[string]interface{}, value interface{}) error {
 	v := reflect.ValueOf(value)
 	if v.Kind() != reflect.Ptr || v.IsNil() {
 		return errors.New("value is invalid")
 	}
 
-	t := v.Type()
-	if t.Implements(unmarshalerType) {
-		if v.Kind() == reflect.Ptr && v.IsNil() {
-			return errors.New("ptr is nil")
+	if m, ok := value.(Unmarshaler); ok {
+		return m.UnmarshalSCIM(data)
+	}
+	return d.decode("", data, v.Elem())
+}
+
+// decode stores the given data in the value, converting it to the type of the value if possible.
+func (d *Decoder) decode(name string, data interface{}, v reflect.Value) error {
+	if data == nil {
+		return nil
+	}
+
+	if v.Kind() != reflect.Ptr && v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		resource, ok := toResource(data)
+		if !ok {
+			return errTypeMismatch(name, data, v.Type())
+		}
+		return v.Addr().Interface().(Unmarshaler).UnmarshalSCIM(resource)
+	}
+
+	s := reflect.ValueOf(data)
+	switch {
+	case v.Type() == timeType:
+		return decodeDateTime(name, data, v)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 && s.Kind() == reflect.String:
+		return decodeBinary(name, s.String(), v)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decode(name, data, v.Elem())
+	case reflect.Interface:
+		if !s.Type().Implements(v.Type()) {
+			return errTypeMismatch(name, data, v.Type())
 		}
-		m, ok := v.Interface().(Unmarshaler)
+		// Store the raw value, this can be a (sub) resource.
+		v.Set(s)
+		return nil
+	case reflect.Struct:
+		resource, ok := toResource(data)
 		if !ok {
-			return errors.New("value does not implement marshaler")
+			return errTypeMismatch(name, data, v.Type())
+		}
+		return d.decodeStruct(resource, v)
+	case reflect.Map:
+		t := v.Type()
+		if s.Kind() != reflect.Map || s.Type().Key().Kind() != reflect.String || t.Key().Kind() != reflect.String {
+			return errTypeMismatch(name, data, t)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(t, s.Len()))
+		}
+		iter := s.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			element := reflect.New(t.Elem()).Elem()
+			if err := d.decode(key, iter.Value().Interface(), element); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), element)
+		}
+		return nil
+	case reflect.Array, reflect.Slice:
+		if s.Kind() != reflect.Array && s.Kind() != reflect.Slice {
+			return errTypeMismatch(name, data, v.Type())
+		}
+		field := v
+		if v.Kind() == reflect.Slice {
+			field = reflect.MakeSlice(v.Type(), s.Len(), s.Len())
+		} else if s.Len() > v.Len() {
+			return fmt.Errorf("too many values for %q: got %d, want at most %d", name, s.Len(), v.Len())
+		}
+		for i := 0; i < s.Len(); i++ {
+			if err := d.decode(name, s.Index(i).Interface(), field.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(field)
+		return nil
+	default:
+		return decodeSimple(name, s, v)
+	}
+}
+
+// decodeStruct fills the fields of the given struct with the values of the corresponding attributes.
+func (d *Decoder) decodeStruct(data map[string]interface{}, v reflect.Value) error {
+	if d.strict {
+		if err := d.unknownAttributes(data, v.Type()); err != nil {
+			return err
 		}
-		return m.UnmarshalSCIM(data)
 	}
 
-	if t.Kind() == reflect.Ptr {
-		v = v.Elem()
-		t = v.Type()
-	}
-
-	for i := 0; i < t.NumField(); i++ {
-		tag := parseTags(t.Field(i))
-		if v := v.Field(i); v.CanAddr() && v.CanSet() {
-			name := lowerFirstRune(tag.name)
-			if fV, ok := data[name]; ok {
-				if fV == nil {
-					continue
-				}
-				s := reflect.ValueOf(fV)
-				switch s.Kind() {
-				case reflect.Array, reflect.Slice:
-					t := toDefaultSlice(fV)
-					if v.Kind() != reflect.Slice {
-						break
-					}
-					field := reflect.MakeSlice(v.Type(), len(t), len(t))
-					for i, v := range t {
-						switch reflect.ValueOf(v).Kind() {
-						case reflect.Map:
-							t := toDefaultMap(v)
-							typ := field.Index(i).Type()
-							element := reflect.New(typ)
-							initializeStruct(typ, element.Elem())
-							if err := Unmarshal(t, element.Interface()); err != nil {
-								return err
-							}
-							field.Index(i).Set(element.Elem())
-						default:
-							field.Index(i).Set(reflect.ValueOf(v))
-						}
-					}
-					v.Set(field)
-					continue
-				case reflect.Map:
-					t := toDefaultMap(fV)
-					field := reflect.New(v.Type())
-					initializeStruct(v.Type(), field.Elem())
-					if err := Unmarshal(t, field.Interface()); err != nil {
-						return err
-					}
-					v.Set(field.Elem())
-					continue
-				}
-				if s.Kind() != v.Kind() {
-					return fmt.Errorf(
-						"types of %q do not match: got %s, want %s",
-						name, s.Type(), v.Type(),
-					)
-				}
-
-				if s.Type() != v.Type() {
-					v.Set(reflect.ValueOf(toType(fV, v.Type())))
-				} else {
-					v.Set(s)
-				}
+	for _, f := range structFields(v.Type()) {
+		name := lowerFirstRune(f.tag.name)
+		value, ok := data[name]
+		if !ok {
+			continue
+		}
+		if field, ok := fieldByIndex(v, f.index, true); ok && field.CanSet() {
+			if value == nil {
+				decodeNull(field)
+				continue
+			}
+			if err := d.decode(name, value, field); err != nil {
+				return err
 			}
 		}
 	}
 	return nil
 }
 
+// unknownAttributes returns an error if the data contains attributes that do not correspond with a field of the given
+// struct type, ignoring the allowed extensions.
+func (d *Decoder) unknownAttributes(data map[string]interface{}, t reflect.Type) error {
+	known := make(map[string]bool)
+	for _, f := range structFields(t) {
+		if f := t.FieldByIndex(f.index); f.PkgPath == "" {
+			known[lowerFirstRune(parseTags(f).name)] = true
+		}
+	}
+
+	var unknown []string
+	for name := range data {
+		if known[name] || d.isExtension(name) {
+			continue
+		}
+		unknown = append(unknown, fmt.Sprintf("%q", name))
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown attributes: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// isExtension checks whether the given name is one of the allowed extension URNs. This check is case insensitive!
+func (d *Decoder) isExtension(name string) bool {
+	for _, e := range d.extensions {
+		if strings.EqualFold(e, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // Unmarshaler is the interface implemented by types that can unmarshal a SCIM description of themselves.
 type Unmarshaler interface {
 	UnmarshalSCIM(map[string]interface{}) error
 }
 
-func initializeStruct(t reflect.Type, v reflect.Value) {
-	for i := 0; i < v.NumField(); i++ {
-		f := v.Field(i)
-		ft := t.Field(i)
-		switch ft.Type.Kind() {
-		case reflect.Map:
-			f.Set(reflect.MakeMap(ft.Type))
-		case reflect.Slice:
-			f.Set(reflect.MakeSlice(ft.Type, 0, 0))
-		case reflect.Struct:
-			initializeStruct(ft.Type, f)
-		case reflect.Ptr:
-			fv := reflect.New(ft.Type.Elem())
-			initializeStruct(ft.Type.Elem(), fv.Elem())
-			f.Set(fv)
+// decodeNull stores an explicit null value in the given field. Empty interfaces are set to Null and pointers to
+// structs to an empty struct, the same values that Marshal converts to null. Other fields are left untouched.
+func decodeNull(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(Null))
+		}
+	case reflect.Ptr:
+		if isComplexType(v.Type().Elem()) {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+	}
+}
+
+// decodeSimple stores the simple value s in v. Numbers get converted to the kind of v if they fit without loss.
+func decodeSimple(name string, s, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		if s.Kind() == reflect.Bool {
+			v.SetBool(s.Bool())
+			return nil
+		}
+	case reflect.String:
+		if s.Kind() == reflect.String {
+			v.SetString(s.String())
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch s.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i = s.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if s.Uint() > math.MaxInt64 {
+				return errOverflow(name, s, v.Type())
+			}
+			i = int64(s.Uint())
+		case reflect.Float32, reflect.Float64:
+			f := s.Float()
+			if f != math.Trunc(f) || f < math.MinInt64 || math.MaxInt64 <= f {
+				return errOverflow(name, s, v.Type())
+			}
+			i = int64(f)
 		default:
+			return errTypeMismatch(name, s.Interface(), v.Type())
 		}
+		if v.OverflowInt(i) {
+			return errOverflow(name, s, v.Type())
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		switch s.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if s.Int() < 0 {
+				return errOverflow(name, s, v.Type())
+			}
+			u = uint64(s.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u = s.Uint()
+		case reflect.Float32, reflect.Float64:
+			f := s.Float()
+			if f != math.Trunc(f) || f < 0 || math.MaxUint64 <= f {
+				return errOverflow(name, s, v.Type())
+			}
+			u = uint64(f)
+		default:
+			return errTypeMismatch(name, s.Interface(), v.Type())
+		}
+		if v.OverflowUint(u) {
+			return errOverflow(name, s, v.Type())
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch s.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			f = float64(s.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			f = float64(s.Uint())
+		case reflect.Float32, reflect.Float64:
+			f = s.Float()
+		default:
+			return errTypeMismatch(name, s.Interface(), v.Type())
+		}
+		if v.OverflowFloat(f) {
+			return errOverflow(name, s, v.Type())
+		}
+		v.SetFloat(f)
+		return nil
+	default:
+		return fmt.Errorf("unsupported type of %q: %s", name, v.Type())
+	}
+	return errTypeMismatch(name, s.Interface(), v.Type())
+}
+
+// decodeDateTime stores the given dateTime value, a string in the RFC3339 format or a time.Time, in v.
+func decodeDateTime(name string, data interface{}, v reflect.Value) error {
+	switch data := data.(type) {
+	case time.Time:
+		v.Set(reflect.ValueOf(data))
+	case string:
+		t, err := time.Parse(time.RFC3339, data)
+		if err != nil {
+			return fmt.Errorf("value of %q is not a valid dateTime: %v", name, err)
+		}
+		v.Set(reflect.ValueOf(t))
+	default:
+		return errTypeMismatch(name, data, v.Type())
 	}
+	return nil
 }
 
-func toDefaultMap(m interface{}) map[string]interface{} {
-	if reflect.TypeOf(m) != mapType {
-		return toType(m, mapType).(map[string]interface{})
+// decodeBinary stores the given base64 encoded binary value in v.
+func decodeBinary(name, data string, v reflect.Value) error {
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return fmt.Errorf("value of %q is not a valid binary: %v", name, err)
 	}
-	return m.(map[string]interface{})
+	v.SetBytes(b)
+	return nil
 }
 
-func toDefaultSlice(m interface{}) []interface{} {
-	if reflect.TypeOf(m) != sliceType {
-		return toType(m, sliceType).([]interface{})
+// toResource converts the given data to a resource if it is a map with string keys.
+func toResource(data interface{}) (map[string]interface{}, bool) {
+	if resource, ok := data.(map[string]interface{}); ok {
+		return resource, true
 	}
-	return m.([]interface{})
+
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	resource := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		resource[iter.Key().String()] = iter.Value().Interface()
+	}
+	return resource, true
+}
+
+func errTypeMismatch(name string, data interface{}, t reflect.Type) error {
+	return fmt.Errorf("types of %q do not match: got %T, want %s", name, data, t)
 }
 
-func toType(i interface{}, t reflect.Type) interface{} {
-	return reflect.
-		ValueOf(i).
-		Convert(t).
-		Interface()
+func errOverflow(name string, s reflect.Value, t reflect.Type) error {
+	return fmt.Errorf("value of %q does not fit in %s: %v", name, t, s.Interface())
 }
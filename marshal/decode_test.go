@@ -172,3 +172,242 @@ func Example() {
 	// map[name:map[familyName:Daenen givenName:Quint] userName:di-wu]
 	// {di-wu {Quint Daenen}}
 }
+
+func TestDecoder_Strict(t *testing.T) {
+	type User struct {
+		UserName string
+		Name     struct {
+			GivenName string
+		}
+	}
+
+	extension := "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	for _, test := range []struct {
+		data  map[string]interface{}
+		valid bool
+	}{
+		{
+			data: map[string]interface{}{
+				"userName": "di-wu",
+				"name": map[string]interface{}{
+					"givenName": "Quint",
+				},
+			},
+			valid: true,
+		},
+		{
+			data: map[string]interface{}{
+				"userName": "di-wu",
+				extension: map[string]interface{}{
+					"employeeNumber": "0001",
+				},
+			},
+			valid: true,
+		},
+		{
+			data: map[string]interface{}{
+				"userName":    "di-wu",
+				"displayName": "Quint",
+			},
+		},
+		{
+			data: map[string]interface{}{
+				"name": map[string]interface{}{
+					"familyName": "Daenen",
+				},
+			},
+		},
+		{
+			data: map[string]interface{}{
+				"urn:ietf:params:scim:schemas:extension:other:2.0:User": map[string]interface{}{},
+			},
+		},
+	} {
+		var user User
+		if err := NewDecoder().Strict(extension).Unmarshal(test.data, &user); (err == nil) != test.valid {
+			t.Errorf("%v: unexpected result: %v", test.data, err)
+		}
+		if err := Unmarshal(test.data, &user); err != nil {
+			t.Errorf("%v: no error expected, got %q", test.data, err)
+		}
+	}
+}
+
+func ExampleDecoder_Strict() {
+	type User struct {
+		UserName string
+	}
+
+	var user User
+	err := NewDecoder().Strict().Unmarshal(map[string]interface{}{
+		"userName":    "di-wu",
+		"displayName": "Quint",
+		"nickName":    "quint",
+	}, &user)
+	fmt.Println(err)
+
+	// Output:
+	// unknown attributes: "displayName", "nickName"
+}
+
+func TestUnmarshal_Embedded(t *testing.T) {
+	type CoreResource struct {
+		ID string `scim:"id"`
+	}
+
+	type Name struct {
+		GivenName string
+	}
+
+	type User struct {
+		CoreResource
+		*Name
+		UserName string
+	}
+
+	var user User
+	if err := NewDecoder().Strict().Unmarshal(map[string]interface{}{
+		"id":        "0001",
+		"givenName": "Quint",
+		"userName":  "di-wu",
+	}, &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "0001" || user.UserName != "di-wu" {
+		t.Error(user)
+	}
+	if user.Name == nil || user.GivenName != "Quint" {
+		t.Error(user.Name)
+	}
+
+	user = User{}
+	if err := Unmarshal(map[string]interface{}{
+		"userName": "di-wu",
+	}, &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != nil {
+		t.Error("embedded pointer should not be allocated")
+	}
+}
+
+type emailType string
+
+type attributeName string
+
+func TestUnmarshal_Types(t *testing.T) {
+	type Email struct {
+		Value string
+		Type  emailType
+	}
+
+	type User struct {
+		UserName string
+		Age      int
+		Score    float32
+		Labels   map[string]string
+		Names    map[attributeName]interface{}
+		Raw      interface{}
+		Types    []emailType
+		Emails   []*Email
+		Manager  *struct {
+			Value string
+		}
+	}
+
+	var user User
+	if err := Unmarshal(map[string]interface{}{
+		"userName": "di-wu",
+		"age":      float64(25),
+		"score":    1,
+		"labels": map[string]interface{}{
+			"team": "core",
+		},
+		"names": map[string]interface{}{
+			"givenName": "Quint",
+		},
+		"raw": map[string]interface{}{
+			"value": "0001",
+		},
+		"types": []interface{}{"work", "home"},
+		"emails": []interface{}{
+			map[string]interface{}{
+				"value": "quint@example.com",
+				"type":  "work",
+			},
+		},
+		"manager": map[string]interface{}{
+			"value": "0002",
+		},
+	}, &user); err != nil {
+		t.Fatal(err)
+	}
+
+	if user.UserName != "di-wu" || user.Age != 25 || user.Score != 1 {
+		t.Error(user)
+	}
+	if user.Labels["team"] != "core" {
+		t.Error(user.Labels)
+	}
+	if user.Names[attributeName("givenName")] != "Quint" {
+		t.Error(user.Names)
+	}
+	if raw, ok := user.Raw.(map[string]interface{}); !ok || raw["value"] != "0001" {
+		t.Error(user.Raw)
+	}
+	if len(user.Types) != 2 || user.Types[0] != "work" || user.Types[1] != "home" {
+		t.Error(user.Types)
+	}
+	if len(user.Emails) != 1 || user.Emails[0].Type != "work" {
+		t.Error(user.Emails)
+	}
+	if user.Manager == nil || user.Manager.Value != "0002" {
+		t.Error(user.Manager)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, data := range []map[string]interface{}{
+			{"userName": 1},
+			{"age": 0.5},
+			{"age": "25"},
+			{"labels": map[string]interface{}{"team": 1}},
+			{"names": []interface{}{"Quint"}},
+			{"types": "work"},
+			{"emails": []interface{}{"quint@example.com"}},
+		} {
+			if err := Unmarshal(data, &User{}); err == nil {
+				t.Errorf("%v: error expected, got none", data)
+			}
+		}
+	})
+}
+
+func TestUnmarshal_NonStruct(t *testing.T) {
+	data := map[string]interface{}{
+		"userName": "di-wu",
+		"name": map[string]interface{}{
+			"givenName": "Quint",
+		},
+	}
+
+	var m map[string]interface{}
+	if err := Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(m) != fmt.Sprint(data) {
+		t.Error(m)
+	}
+
+	var i interface{}
+	if err := Unmarshal(data, &i); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(i) != fmt.Sprint(data) {
+		t.Error(i)
+	}
+
+	var s string
+	if err := Unmarshal(data, &s); err == nil {
+		t.Error("error expected, got none")
+	}
+}
@@ -1,14 +1,27 @@
 package marshal
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	. "github.com/scim2/tools/attributes"
 )
 
-var marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+var (
+	marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	nullType      = reflect.TypeOf(Null)
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// Null represents an explicit null value. An attribute (or map value) that is set to Null gets marshalled as null,
+// which clears the value of the attribute in a SCIM PUT request. This is different from unassigned attributes (nil or
+// zero values), which are omitted.
+var Null = null{}
+
+type null struct{}
 
 func Marshal(value interface{}) (map[string]interface{}, error) {
 	v := reflect.ValueOf(value)
@@ -35,25 +48,14 @@ func Marshal(value interface{}) (map[string]interface{}, error) {
 		}
 		return Marshal(v.Elem().Interface())
 	case reflect.Ptr:
-		ptr := v.Elem()
-		return Marshal(ptr.Elem().Interface())
+		if v.IsNil() {
+			return nil, errors.New("ptr is nil")
+		}
+		return Marshal(v.Elem().Interface())
 	case reflect.Struct:
 		resource := make(map[string]interface{})
-
-		t := v.Type()
-		for i := 0; i < v.NumField(); i++ {
-			tag := parseTags(t.Field(i))
-			if tag.ignore {
-				continue
-			}
-
-			field := v.Field(i)
-			if !tag.allowZero && field.IsZero() {
-				continue
-			}
-			if err := structEncoder(resource, field, tag); err != nil {
-				return nil, err
-			}
+		if err := structEncoderFields(resource, v, true); err != nil {
+			return nil, err
 		}
 		return resource, nil
 	default:
@@ -61,7 +63,65 @@ func Marshal(value interface{}) (map[string]interface{}, error) {
 	}
 }
 
+// structEncoderFields encodes the fields of the given struct into the resource. Inline fields are only supported in
+// the root of the resource, these get marshalled into a separate resource that is stored under the name of the field.
+func structEncoderFields(resource map[string]interface{}, v reflect.Value, root bool) error {
+	for _, f := range structFields(v.Type()) {
+		if f.tag.ignore {
+			continue
+		}
+
+		field, ok := fieldByIndex(v, f.index, false)
+		if !ok {
+			continue
+		}
+		if !f.tag.allowZero && field.IsZero() {
+			continue
+		}
+		if root && f.tag.inline {
+			if err := structEncoderInline(resource, field, f.tag); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := structEncoder(resource, field, f.tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func structEncoderInline(resource map[string]interface{}, field reflect.Value, tag tag) error {
+	if isNull(field) {
+		return Add(resource, tag.name, nil)
+	}
+	for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+	if !field.CanInterface() {
+		return fmt.Errorf("inline attribute is not exported: %s", tag.name)
+	}
+
+	extension, err := Marshal(field.Interface())
+	if err != nil {
+		return err
+	}
+	return Add(resource, tag.name, extension)
+}
+
 func structEncoder(resource map[string]interface{}, field reflect.Value, tag tag) error {
+	if isNull(field) {
+		if tag.sub == nil {
+			return Add(resource, tag.name, nil)
+		}
+		if !tag.multiValued {
+			return Add(EnsureComplexAttribute(resource, tag.name), tag.sub.name, nil)
+		}
+	}
+
 	if tag.sub == nil {
 		if tag.multiValued {
 			return structEncoderSimpleMultiValued(resource, field, tag)
@@ -116,6 +176,9 @@ func structEncoderSimple(resource map[string]interface{}, field reflect.Value, t
 	if !field.IsValid() {
 		return nil
 	}
+	if value, ok := encodeString(field); ok {
+		return Add(resource, tag.name, value)
+	}
 
 	switch field.Kind() {
 	// If the simple attribute is a map that means that it is in fact a complex attribute where the name is implicit.
@@ -132,6 +195,12 @@ func structEncoderSimple(resource map[string]interface{}, field reflect.Value, t
 
 		for _, k := range field.MapKeys() {
 			value := field.MapIndex(k)
+			if isNull(value) {
+				if err := Add(mapField, k.String(), nil); err != nil {
+					return err
+				}
+				continue
+			}
 
 			// If the value is an interface or ptr, use the underlying element.
 			for value.Kind() == reflect.Interface ||
@@ -151,20 +220,8 @@ func structEncoderSimple(resource map[string]interface{}, field reflect.Value, t
 		return structEncoderSimple(resource, field.Elem(), tag)
 	case reflect.Struct:
 		fieldStruct := make(map[string]interface{})
-		t := field.Type()
-		for i := 0; i < field.NumField(); i++ {
-			tagIndex := parseTags(t.Field(i))
-			if tagIndex.ignore {
-				continue
-			}
-
-			fieldIndex := field.Field(i)
-			if !tagIndex.allowZero && fieldIndex.IsZero() {
-				continue
-			}
-			if err := structEncoder(fieldStruct, fieldIndex, tagIndex); err != nil {
-				return err
-			}
+		if err := structEncoderFields(fieldStruct, field, false); err != nil {
+			return err
 		}
 		if depth := Depth(fieldStruct); 1 < depth {
 			return fmt.Errorf("nested depth exceeded: %d", depth)
@@ -194,16 +251,24 @@ func structEncoderSimple(resource map[string]interface{}, field reflect.Value, t
 func structEncoderSimpleMultiValued(resource map[string]interface{}, field reflect.Value, tag tag) error {
 	switch field.Kind() {
 	case reflect.Array, reflect.Slice:
+		// An empty (non nil) slice clears the multi valued attribute.
+		if field.Len() == 0 {
+			return Add(resource, tag.name, []interface{}{})
+		}
 		for i := 0; i < field.Len(); i++ {
 			value := make(map[string]interface{})
 			if err := structEncoderSimple(value, field.Index(i), tag); err != nil {
 				return err
 			}
 			for _, v := range value {
-				switch field.Index(i).Kind() {
-				case reflect.Struct:
+				switch element := field.Index(i); {
+				case element.Kind() == reflect.Struct && element.Type() != timeType:
+					complexValue, ok := v.(map[string]interface{})
+					if !ok {
+						return fmt.Errorf("invalid complex attribute: %s", tag.name)
+					}
 					EnsureComplexMultiValuedAttribute(resource, tag.name, tag.max())
-					if err := AppendComplexMultiValuedAttribute(resource, tag.name, v.(map[string]interface{})); err != nil {
+					if err := AppendComplexMultiValuedAttribute(resource, tag.name, complexValue); err != nil {
 						return err
 					}
 				default:
@@ -221,7 +286,11 @@ func structEncoderSimpleMultiValued(resource map[string]interface{}, field refle
 			return err
 		}
 		for _, v := range value {
-			if err := AppendComplexMultiValuedAttribute(resource, tag.name, v.(map[string]interface{})); err != nil {
+			complexValue, ok := v.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("invalid complex attribute: %s", tag.name)
+			}
+			if err := AppendComplexMultiValuedAttribute(resource, tag.name, complexValue); err != nil {
 				return err
 			}
 		}
@@ -230,20 +299,8 @@ func structEncoderSimpleMultiValued(resource map[string]interface{}, field refle
 	case reflect.Struct:
 		EnsureComplexMultiValuedAttribute(resource, tag.name, tag.max())
 		fieldStruct := make(map[string]interface{})
-		t := field.Type()
-		for i := 0; i < field.NumField(); i++ {
-			tagIndex := parseTags(t.Field(i))
-			if tagIndex.ignore {
-				continue
-			}
-
-			fieldIndex := field.Field(i)
-			if !tagIndex.allowZero && fieldIndex.IsZero() {
-				continue
-			}
-			if err := structEncoder(fieldStruct, fieldIndex, tagIndex); err != nil {
-				return err
-			}
+		if err := structEncoderFields(fieldStruct, field, false); err != nil {
+			return err
 		}
 		if depth := Depth(fieldStruct); 1 < depth {
 			return fmt.Errorf("nested depth exceeded: %d", depth)
@@ -267,6 +324,26 @@ func structEncoderSimpleMultiValued(resource map[string]interface{}, field refle
 	return nil
 }
 
+// isNull checks whether the value represents an explicit null value. This is the case for Null and (non nil)
+// pointers to structs that only contain zero values. A pointer to a zero time.Time is a (zero) dateTime, not null.
+func isNull(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface:
+		return !v.IsNil() && isNull(v.Elem())
+	case reflect.Ptr:
+		return !v.IsNil() && isComplexType(v.Type().Elem()) && v.Elem().IsZero()
+	case reflect.Struct:
+		return v.Type() == nullType
+	default:
+		return false
+	}
+}
+
+// isComplexType checks whether values of the given type are complex values, i.e. structs other than time.Time.
+func isComplexType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
 func unsupportedTypeEncoder(v reflect.Value) (map[string]interface{}, error) {
 	return nil, errors.New(fmt.Sprintf("unsupported type %s", v.Type()))
 }
@@ -279,6 +356,9 @@ func validSimpleAttribute(v reflect.Value) (interface{}, error) {
 
 		v = v.Elem()
 	}
+	if value, ok := encodeString(v); ok {
+		return value, nil
+	}
 
 	switch v.Kind() {
 	case reflect.Bool:
@@ -296,6 +376,21 @@ func validSimpleAttribute(v reflect.Value) (interface{}, error) {
 	}
 }
 
+// encodeString converts the values that are represented by strings in SCIM. A time.Time is a dateTime in the RFC3339
+// format and a []byte is a base64 encoded binary value.
+func encodeString(v reflect.Value) (string, bool) {
+	switch {
+	case !v.IsValid():
+		return "", false
+	case v.Type() == timeType && v.CanInterface():
+		return v.Interface().(time.Time).Format(time.RFC3339), true
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return base64.StdEncoding.EncodeToString(v.Bytes()), true
+	default:
+		return "", false
+	}
+}
+
 // Marshaler is the interface implemented by types that can marshal themselves into SCIM resources.
 type Marshaler interface {
 	MarshalSCIM() (map[string]interface{}, error)
@@ -3,6 +3,7 @@ package marshal
 import (
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestComplex(t *testing.T) {
@@ -305,3 +306,331 @@ func TestSimpleMultiValued(t *testing.T) {
 		t.Error(fmt.Sprintf("\n%#v", resource), fmt.Sprintf("\n%#v", ref))
 	}
 }
+
+func TestEmbedded(t *testing.T) {
+	type Meta struct {
+		ResourceType string
+	}
+
+	type CoreResource struct {
+		ID         string `scim:"id"`
+		ExternalID string `scim:"externalId"`
+		Meta       Meta
+	}
+
+	type Name struct {
+		GivenName string
+	}
+
+	type EnterpriseUser struct {
+		EmployeeNumber string
+		Manager        struct {
+			Value string
+		}
+	}
+
+	t.Run("flatten", func(t *testing.T) {
+		type User struct {
+			CoreResource
+			*Name
+			UserName string
+		}
+
+		resource, err := Marshal(User{
+			CoreResource: CoreResource{
+				ID:   "0001",
+				Meta: Meta{ResourceType: "User"},
+			},
+			Name:     &Name{GivenName: "Quint"},
+			UserName: "di-wu",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref := map[string]interface{}{
+			"givenName": "Quint",
+			"id":        "0001",
+			"meta":      map[string]interface{}{"resourceType": "User"},
+			"userName":  "di-wu",
+		}
+		if fmt.Sprintf("%#v", resource) != fmt.Sprintf("%#v", ref) {
+			t.Error(fmt.Sprintf("\n%#v", resource), fmt.Sprintf("\n%#v", ref))
+		}
+
+		if _, err := Marshal(User{UserName: "di-wu"}); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("hidden", func(t *testing.T) {
+		type User struct {
+			CoreResource
+			ID string `scim:"id"`
+		}
+
+		resource, err := Marshal(User{
+			CoreResource: CoreResource{ID: "0001"},
+			ID:           "0002",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := resource["id"]; id != "0002" {
+			t.Errorf("expected 0002, got %v", id)
+		}
+	})
+
+	t.Run("named", func(t *testing.T) {
+		type User struct {
+			Name `scim:"name"`
+		}
+
+		resource, err := Marshal(User{Name{GivenName: "Quint"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref := map[string]interface{}{
+			"name": map[string]interface{}{"givenName": "Quint"},
+		}
+		if fmt.Sprintf("%#v", resource) != fmt.Sprintf("%#v", ref) {
+			t.Error(fmt.Sprintf("\n%#v", resource), fmt.Sprintf("\n%#v", ref))
+		}
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		type User struct {
+			UserName        string
+			*EnterpriseUser `scim:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,inline"`
+		}
+
+		user := User{
+			UserName:       "di-wu",
+			EnterpriseUser: &EnterpriseUser{EmployeeNumber: "0001"},
+		}
+		user.Manager.Value = "0002"
+		resource, err := Marshal(user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref := map[string]interface{}{
+			"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User": map[string]interface{}{
+				"employeeNumber": "0001",
+				"manager":        map[string]interface{}{"value": "0002"},
+			},
+			"userName": "di-wu",
+		}
+		if fmt.Sprintf("%#v", resource) != fmt.Sprintf("%#v", ref) {
+			t.Error(fmt.Sprintf("\n%#v", resource), fmt.Sprintf("\n%#v", ref))
+		}
+	})
+}
+
+func TestMarshal_Ptr(t *testing.T) {
+	type User struct {
+		UserName string
+	}
+
+	user := &User{UserName: "di-wu"}
+	for _, value := range []interface{}{user, &user} {
+		resource, err := Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resource["userName"] != "di-wu" {
+			t.Error(resource)
+		}
+	}
+
+	var nilUser *User
+	for _, value := range []interface{}{nil, nilUser, &nilUser} {
+		if _, err := Marshal(value); err == nil {
+			t.Error("error expected, got none")
+		}
+	}
+}
+
+func TestMarshal_NilMapValue(t *testing.T) {
+	type User struct {
+		Labels interface{}
+	}
+
+	for _, labels := range []interface{}{
+		map[string]interface{}{"team": nil},
+		map[string]*string{"team": nil},
+	} {
+		if _, err := Marshal(User{Labels: labels}); err == nil {
+			t.Errorf("error expected for %#v, got none", labels)
+		}
+	}
+}
+
+func TestNull(t *testing.T) {
+	type Manager struct {
+		Value string
+	}
+
+	type User struct {
+		Active       bool    `scim:"active,zero"`
+		NickName     *string `scim:"nickName"`
+		Title        interface{}
+		Manager      *Manager `scim:"manager"`
+		PhoneNumbers []string `scim:"phoneNumbers,mV"`
+		Labels       map[string]interface{}
+	}
+
+	empty := ""
+	resource, err := Marshal(User{
+		NickName:     &empty,
+		Title:        Null,
+		Manager:      &Manager{},
+		PhoneNumbers: []string{},
+		Labels: map[string]interface{}{
+			"team": Null,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := map[string]interface{}{
+		"active":       false,
+		"labels":       map[string]interface{}{"team": nil},
+		"manager":      nil,
+		"nickName":     "",
+		"phoneNumbers": []interface{}{},
+		"title":        nil,
+	}
+	if fmt.Sprintf("%#v", resource) != fmt.Sprintf("%#v", ref) {
+		t.Error(fmt.Sprintf("\n%#v", resource), fmt.Sprintf("\n%#v", ref))
+	}
+
+	resource, err = Marshal(User{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref := map[string]interface{}{"active": false}; fmt.Sprintf("%#v", resource) != fmt.Sprintf("%#v", ref) {
+		t.Error(fmt.Sprintf("\n%#v", resource), fmt.Sprintf("\n%#v", ref))
+	}
+
+	t.Run("time", func(t *testing.T) {
+		type User struct {
+			LastModified *time.Time `scim:"lastModified"`
+		}
+
+		resource, err := Marshal(User{LastModified: &time.Time{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := resource["lastModified"]; v != "0001-01-01T00:00:00Z" {
+			t.Errorf("expected the zero dateTime, got %#v", v)
+		}
+
+		var user User
+		if err := Unmarshal(map[string]interface{}{"lastModified": nil}, &user); err != nil {
+			t.Fatal(err)
+		}
+		if user.LastModified != nil {
+			t.Errorf("expected nil, got %v", user.LastModified)
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		var user User
+		if err := Unmarshal(map[string]interface{}{
+			"title":        nil,
+			"manager":      nil,
+			"phoneNumbers": []interface{}{},
+		}, &user); err != nil {
+			t.Fatal(err)
+		}
+		if user.Title != Null {
+			t.Errorf("expected null, got %v", user.Title)
+		}
+		if user.Manager == nil || *user.Manager != (Manager{}) {
+			t.Errorf("expected empty manager, got %v", user.Manager)
+		}
+		if user.PhoneNumbers == nil || len(user.PhoneNumbers) != 0 {
+			t.Errorf("expected empty phone numbers, got %v", user.PhoneNumbers)
+		}
+	})
+}
+
+func TestDateTimeBinary(t *testing.T) {
+	type Certificate struct {
+		Value []byte `scim:"value"`
+	}
+
+	type User struct {
+		Created      time.Time     `scim:"created"`
+		LastModified *time.Time    `scim:"lastModified"`
+		Logins       []time.Time   `scim:"logins,mV"`
+		Photo        []byte        `scim:"photo"`
+		Certificates []Certificate `scim:"x509Certificates,mV"`
+	}
+
+	created := time.Date(2010, 1, 23, 4, 56, 22, 0, time.UTC)
+	modified := time.Date(2011, 5, 13, 4, 42, 34, 0, time.FixedZone("", -7*60*60))
+	user := User{
+		Created:      created,
+		LastModified: &modified,
+		Logins:       []time.Time{created, modified},
+		Photo:        []byte("photo"),
+		Certificates: []Certificate{{Value: []byte("cert")}},
+	}
+	resource, err := Marshal(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := map[string]interface{}{
+		"created":          "2010-01-23T04:56:22Z",
+		"lastModified":     "2011-05-13T04:42:34-07:00",
+		"logins":           []interface{}{"2010-01-23T04:56:22Z", "2011-05-13T04:42:34-07:00"},
+		"photo":            "cGhvdG8=",
+		"x509Certificates": []map[string]interface{}{{"value": "Y2VydA=="}},
+	}
+	if fmt.Sprintf("%#v", resource) != fmt.Sprintf("%#v", ref) {
+		t.Error(fmt.Sprintf("\n%#v", resource), fmt.Sprintf("\n%#v", ref))
+	}
+
+	var decoded User
+	if err := Unmarshal(resource, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Created.Equal(created) || !decoded.LastModified.Equal(modified) || len(decoded.Logins) != 2 ||
+		string(decoded.Photo) != "photo" || string(decoded.Certificates[0].Value) != "cert" {
+		t.Errorf("unexpected user: %+v", decoded)
+	}
+
+	for _, data := range []map[string]interface{}{
+		{"created": "yesterday"},
+		{"created": 1},
+		{"photo": "not base64"},
+	} {
+		if err := Unmarshal(data, &decoded); err == nil {
+			t.Errorf("expected an error for %v", data)
+		}
+	}
+}
+
+func ExampleNull() {
+	type Manager struct {
+		Value string
+	}
+
+	type User struct {
+		UserName     string
+		DisplayName  interface{}
+		Manager      *Manager
+		PhoneNumbers []string `scim:",mV"`
+	}
+
+	resource, _ := Marshal(User{
+		UserName:     "di-wu",    // assigned
+		DisplayName:  Null,       // cleared
+		Manager:      &Manager{}, // cleared
+		PhoneNumbers: []string{}, // cleared
+	})
+	fmt.Println(resource)
+
+	// Output:
+	// map[displayName:<nil> manager:<nil> phoneNumbers:[] userName:di-wu]
+}
@@ -0,0 +1,106 @@
+package marshal
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// field represents a field of a struct, fields of embedded structs are promoted to the struct that embeds them.
+type field struct {
+	index []int
+	tag   tag
+}
+
+// structFields returns the fields of the given struct type in the order they are declared. Embedded structs without
+// an explicit name in their tag are flattened into the parent struct, similar to encoding/json. A field with a
+// lesser depth hides promoted fields with the same name, fields with the same name on the same depth hide each other.
+func structFields(t reflect.Type) []field {
+	var fields []field
+	collectFields(t, nil, map[reflect.Type]bool{}, &fields)
+
+	// Sort on name, then on depth, to find the dominant fields.
+	sort.SliceStable(fields, func(i, j int) bool {
+		if a, b := fields[i].tag.path(), fields[j].tag.path(); a != b {
+			return a < b
+		}
+		return len(fields[i].index) < len(fields[j].index)
+	})
+
+	var dominant []field
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].tag.path() == fields[i].tag.path() {
+			j++
+		}
+		if j-i == 1 || len(fields[i].index) < len(fields[i+1].index) {
+			dominant = append(dominant, fields[i])
+		}
+		i = j
+	}
+
+	// Restore the declaration order.
+	sort.Slice(dominant, func(i, j int) bool {
+		a, b := dominant[i].index, dominant[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return dominant
+}
+
+func collectFields(t reflect.Type, index []int, visited map[reflect.Type]bool, fields *[]field) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		tag := parseTags(f)
+
+		if f.Anonymous {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !tag.ignore && !hasExplicitName(f) {
+				collectFields(ft, fieldIndex, visited, fields)
+				continue
+			}
+		}
+
+		*fields = append(*fields, field{
+			index: fieldIndex,
+			tag:   tag,
+		})
+	}
+}
+
+// hasExplicitName checks whether the scim tag of the given field contains a name.
+func hasExplicitName(f reflect.StructField) bool {
+	return strings.Split(f.Tag.Get("scim"), ",")[0] != ""
+}
+
+// fieldByIndex returns the nested field corresponding to the given index. It returns false if the path to the field
+// passes through a nil pointer to an embedded struct. If alloc is true, these pointers get allocated if possible.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
@@ -0,0 +1,109 @@
+package marshal
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type fuzzEmail struct {
+	Value   string
+	Type    emailType
+	Primary *bool
+}
+
+type fuzzCore struct {
+	ID string `scim:"id"`
+}
+
+type fuzzUser struct {
+	fuzzCore
+	UserName   string
+	Active     bool
+	Age        int8
+	Score      float32
+	Created    string
+	Photo      []byte
+	ProfileURL *string `scim:"profileUrl"`
+	NickNames  [2]string
+	Name       *struct {
+		GivenName  string
+		FamilyName interface{}
+	}
+	Emails []fuzzEmail
+	Extra  map[attributeName]*uint16
+	Labels map[string]interface{}
+	Tags   map[string]*string
+	Self   *fuzzUser
+}
+
+// fuzzTargets returns the values that the fuzzed resources get decoded into.
+func fuzzTargets() []interface{} {
+	var i interface{}
+	return []interface{}{
+		&fuzzUser{},
+		&map[string]interface{}{},
+		&map[string]string{},
+		&i,
+		&testUnmarshal{},
+	}
+}
+
+// fuzzRoundTrip decodes the data into every target, both lenient and strict. The values that decode are marshalled,
+// decoded again and marshalled again, which should result in the same resource.
+func fuzzRoundTrip(t testing.TB, data map[string]interface{}) {
+	for _, target := range fuzzTargets() {
+		_ = NewDecoder().Strict().Unmarshal(data, target)
+	}
+	for _, target := range fuzzTargets() {
+		if err := Unmarshal(data, target); err != nil {
+			continue
+		}
+		resource, err := Marshal(target)
+		if err != nil {
+			continue
+		}
+		decoded := reflect.New(reflect.TypeOf(target).Elem()).Interface()
+		if err := Unmarshal(resource, decoded); err != nil {
+			t.Fatalf("could not decode %v into %T: %v", resource, decoded, err)
+		}
+		again, err := Marshal(decoded)
+		if err != nil {
+			t.Fatalf("could not marshal %+v: %v", decoded, err)
+		}
+		if !reflect.DeepEqual(resource, again) {
+			t.Fatalf("round trip of %T changed the resource:\n%#v\n%#v", target, resource, again)
+		}
+	}
+}
+
+// FuzzUnmarshal also uses the resources of the Fuzzer in testdata/fuzz/FuzzUnmarshal as seeds.
+func FuzzUnmarshal(f *testing.F) {
+	// Values are not limited to the types that encoding/json produces.
+	fuzzRoundTrip(f, map[string]interface{}{
+		"userName":  "di-wu",
+		"age":       -2536585264029398786,
+		"score":     float32(0.75),
+		"nickNames": []string{"quint"},
+		"name":      map[string]string{"givenName": "Quint"},
+		"emails":    []map[string]interface{}{{"value": "quint@example.com", "primary": true}},
+		"labels":    map[string]interface{}{"team": nil},
+	})
+
+	for _, raw := range []string{
+		`{"age": 1e300, "score": -1e300, "nickNames": ["a", "b", "c"]}`,
+		`{"photo": "aGk=", "emails": [null, {"value": {}}, []], "extra": {"x": -1}}`,
+		`{"self": {"self": {"self": {"userName": "di-wu"}}}, "id": [], "name": "Quint"}`,
+		`{"labels": {"team": null, "size": 3}, "tags": {"team": null}}`,
+	} {
+		f.Add([]byte(raw))
+	}
+
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		var data map[string]interface{}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			t.Skip()
+		}
+		fuzzRoundTrip(t, data)
+	})
+}
@@ -1,5 +1,5 @@
 module github.com/scim2/tools/marshal
 
-go 1.15
+go 1.18
 
 require github.com/scim2/tools/attributes v1.0.0
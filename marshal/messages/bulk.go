@@ -0,0 +1,189 @@
+package messages
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// BulkRequest is the body of a POST request to the "/Bulk" endpoint. A zero FailOnErrors is not included in the
+// request, which means that the service provider continues after errors.
+type BulkRequest struct {
+	FailOnErrors int
+	Operations   []BulkOperation
+}
+
+// BulkOperation is an operation of a bulk request. The data is marshalled the same way as the value of a
+// PatchOperation, unmarshalled data is kept as is.
+type BulkOperation struct {
+	Method  string
+	BulkID  string
+	Version string
+	Path    string
+	Data    interface{}
+}
+
+func (r BulkRequest) MarshalSCIM() (map[string]interface{}, error) {
+	operations := make([]interface{}, len(r.Operations))
+	for i, op := range r.Operations {
+		operation := map[string]interface{}{
+			"method": op.Method,
+			"path":   op.Path,
+		}
+		addString(operation, "bulkId", op.BulkID)
+		addString(operation, "version", op.Version)
+		if op.Data != nil {
+			data, err := marshalValue(op.Data)
+			if err != nil {
+				return nil, fmt.Errorf("operation %d: %v", i, err)
+			}
+			operation["data"] = data
+		}
+		operations[i] = operation
+	}
+	data := map[string]interface{}{
+		"schemas":    []interface{}{BulkRequestSchema},
+		"Operations": operations,
+	}
+	if r.FailOnErrors != 0 {
+		data["failOnErrors"] = r.FailOnErrors
+	}
+	return data, nil
+}
+
+func (r *BulkRequest) UnmarshalSCIM(data map[string]interface{}) error {
+	var request struct {
+		FailOnErrors int
+		Operations   []map[string]interface{}
+	}
+	if err := unmarshal(data, BulkRequestSchema, &request); err != nil {
+		return err
+	}
+
+	operations := make([]BulkOperation, len(request.Operations))
+	for i, op := range request.Operations {
+		var operation struct {
+			Method  string
+			BulkID  string `scim:"bulkId"`
+			Version string
+			Path    string
+			Data    interface{}
+		}
+		if err := unmarshal(op, "", &operation); err != nil {
+			return fmt.Errorf("operation %d: %v", i, err)
+		}
+		if operation.Method == "" {
+			return fmt.Errorf("operation %d: method is missing", i)
+		}
+		operations[i] = BulkOperation(operation)
+	}
+	*r = BulkRequest{FailOnErrors: request.FailOnErrors, Operations: operations}
+	return nil
+}
+
+// BulkResponse is the response of a bulk request.
+type BulkResponse struct {
+	Operations []BulkResponseOperation
+}
+
+// BulkResponseOperation is the result of an operation of a bulk request. The response is marshalled the same way as
+// the value of a PatchOperation, e.g. it can be an Error. Unmarshalled responses are kept as is, ErrorResponse converts
+// the response of a failed operation into an Error.
+type BulkResponseOperation struct {
+	Method   string
+	BulkID   string
+	Version  string
+	Location string
+	Status   int
+	Response interface{}
+}
+
+// ErrorResponse returns the response of the operation as an Error, or nil if the operation did not fail.
+func (o BulkResponseOperation) ErrorResponse() (*Error, error) {
+	if o.Status < 400 {
+		return nil, nil
+	}
+	switch response := o.Response.(type) {
+	case *Error:
+		return response, nil
+	case Error:
+		return &response, nil
+	case map[string]interface{}:
+		var e Error
+		if err := e.UnmarshalSCIM(response); err != nil {
+			return nil, err
+		}
+		return &e, nil
+	default:
+		return &Error{Status: o.Status}, nil
+	}
+}
+
+func (r BulkResponse) MarshalSCIM() (map[string]interface{}, error) {
+	operations := make([]interface{}, len(r.Operations))
+	for i, op := range r.Operations {
+		operation := map[string]interface{}{
+			"method": op.Method,
+			"status": strconv.Itoa(op.Status),
+		}
+		addString(operation, "bulkId", op.BulkID)
+		addString(operation, "version", op.Version)
+		addString(operation, "location", op.Location)
+		if op.Response != nil {
+			response, err := marshalValue(op.Response)
+			if err != nil {
+				return nil, fmt.Errorf("operation %d: %v", i, err)
+			}
+			operation["response"] = response
+		}
+		operations[i] = operation
+	}
+	return map[string]interface{}{
+		"schemas":    []interface{}{BulkResponseSchema},
+		"Operations": operations,
+	}, nil
+}
+
+func (r *BulkResponse) UnmarshalSCIM(data map[string]interface{}) error {
+	var response struct {
+		Operations []map[string]interface{}
+	}
+	if err := unmarshal(data, BulkResponseSchema, &response); err != nil {
+		return err
+	}
+
+	operations := make([]BulkResponseOperation, len(response.Operations))
+	for i, op := range response.Operations {
+		var operation struct {
+			Method   string
+			BulkID   string `scim:"bulkId"`
+			Version  string
+			Location string
+			Status   interface{}
+			Response interface{}
+		}
+		if err := unmarshal(op, "", &operation); err != nil {
+			return fmt.Errorf("operation %d: %v", i, err)
+		}
+		status, err := parseStatus(operation.Status)
+		if err != nil {
+			return fmt.Errorf("operation %d: %v", i, err)
+		}
+		operations[i] = BulkResponseOperation{
+			Method:   operation.Method,
+			BulkID:   operation.BulkID,
+			Version:  operation.Version,
+			Location: operation.Location,
+			Status:   status,
+			Response: operation.Response,
+		}
+	}
+	*r = BulkResponse{Operations: operations}
+	return nil
+}
+
+// addString adds the given string value to the data if it is not empty.
+func addString(data map[string]interface{}, name, value string) {
+	if value != "" {
+		data[name] = value
+	}
+}
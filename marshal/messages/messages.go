@@ -0,0 +1,287 @@
+// Package messages contains the messages of the SCIM protocol (RFC 7644), e.g. list responses, patch operations and
+// errors. All the messages implement the Marshaler and Unmarshaler interfaces of the marshal package and carry the URN
+// of their schema in the "schemas" attribute.
+package messages
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scim2/tools/marshal"
+)
+
+// The URNs of the schemas of the messages.
+const (
+	ListResponseSchema  = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SearchRequestSchema = "urn:ietf:params:scim:api:messages:2.0:SearchRequest"
+	PatchOpSchema       = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	BulkRequestSchema   = "urn:ietf:params:scim:api:messages:2.0:BulkRequest"
+	BulkResponseSchema  = "urn:ietf:params:scim:api:messages:2.0:BulkResponse"
+	ErrorSchema         = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// ListResponse is the response of a query. The resources are marshalled with marshal.Marshal and unmarshalled with
+// marshal.Unmarshal, so T can be any struct supported by the marshal package, or map[string]interface{}.
+type ListResponse[T any] struct {
+	TotalResults int
+	ItemsPerPage int
+	StartIndex   int
+	Resources    []T
+}
+
+func (l ListResponse[T]) MarshalSCIM() (map[string]interface{}, error) {
+	resources := make([]interface{}, 0, len(l.Resources))
+	for _, r := range l.Resources {
+		resource, err := marshalValue(r)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	data := map[string]interface{}{
+		"schemas":      []interface{}{ListResponseSchema},
+		"totalResults": l.TotalResults,
+		"Resources":    resources,
+	}
+	if l.ItemsPerPage != 0 {
+		data["itemsPerPage"] = l.ItemsPerPage
+	}
+	if l.StartIndex != 0 {
+		data["startIndex"] = l.StartIndex
+	}
+	return data, nil
+}
+
+func (l *ListResponse[T]) UnmarshalSCIM(data map[string]interface{}) error {
+	var response struct {
+		TotalResults int
+		ItemsPerPage int
+		StartIndex   int
+		Resources    []map[string]interface{}
+	}
+	if err := unmarshal(data, ListResponseSchema, &response); err != nil {
+		return err
+	}
+
+	resources := make([]T, len(response.Resources))
+	for i, r := range response.Resources {
+		if err := marshal.Unmarshal(r, &resources[i]); err != nil {
+			return fmt.Errorf("resource %d: %v", i, err)
+		}
+	}
+	*l = ListResponse[T]{
+		TotalResults: response.TotalResults,
+		ItemsPerPage: response.ItemsPerPage,
+		StartIndex:   response.StartIndex,
+		Resources:    resources,
+	}
+	return nil
+}
+
+// The sort orders of a search request.
+const (
+	Ascending  = "ascending"
+	Descending = "descending"
+)
+
+// SearchRequest is the body of a POST request to the ".search" endpoint. A nil count is not included in the request,
+// the other zero values are omitted as well.
+type SearchRequest struct {
+	Attributes         []string `scim:"attributes,mV"`
+	ExcludedAttributes []string `scim:"excludedAttributes,mV"`
+	Filter             string   `scim:"filter"`
+	SortBy             string   `scim:"sortBy"`
+	SortOrder          string   `scim:"sortOrder"`
+	StartIndex         int      `scim:"startIndex"`
+	Count              *int     `scim:"count"`
+}
+
+func (r SearchRequest) MarshalSCIM() (map[string]interface{}, error) {
+	type searchRequest SearchRequest // without the methods
+	data, err := marshal.Marshal(searchRequest(r))
+	if err != nil {
+		return nil, err
+	}
+	data["schemas"] = []interface{}{SearchRequestSchema}
+	return data, nil
+}
+
+func (r *SearchRequest) UnmarshalSCIM(data map[string]interface{}) error {
+	type searchRequest SearchRequest
+	var request searchRequest
+	if err := unmarshal(data, SearchRequestSchema, &request); err != nil {
+		return err
+	}
+	*r = SearchRequest(request)
+	return nil
+}
+
+// The SCIM types of errors, the "scimType" attribute of an error response with status 400.
+const (
+	InvalidFilter = "invalidFilter"
+	TooMany       = "tooMany"
+	Uniqueness    = "uniqueness"
+	Mutability    = "mutability"
+	InvalidSyntax = "invalidSyntax"
+	InvalidPath   = "invalidPath"
+	NoTarget      = "noTarget"
+	InvalidValue  = "invalidValue"
+	InvalidVers   = "invalidVers"
+	Sensitive     = "sensitive"
+)
+
+// Error is an error response of the service provider. The status is a string in the message, e.g. "400", but a number
+// is accepted as well.
+type Error struct {
+	Status   int
+	SCIMType string
+	Detail   string
+}
+
+func (e *Error) Error() string {
+	if e.SCIMType != "" {
+		return fmt.Sprintf("scim: %d %s: %s", e.Status, e.SCIMType, e.Detail)
+	}
+	return fmt.Sprintf("scim: %d: %s", e.Status, e.Detail)
+}
+
+func (e Error) MarshalSCIM() (map[string]interface{}, error) {
+	data := map[string]interface{}{
+		"schemas": []interface{}{ErrorSchema},
+		"status":  strconv.Itoa(e.Status),
+	}
+	if e.SCIMType != "" {
+		data["scimType"] = e.SCIMType
+	}
+	if e.Detail != "" {
+		data["detail"] = e.Detail
+	}
+	return data, nil
+}
+
+func (e *Error) UnmarshalSCIM(data map[string]interface{}) error {
+	var response struct {
+		Status   interface{}
+		SCIMType string `scim:"scimType"`
+		Detail   string
+	}
+	if err := unmarshal(data, ErrorSchema, &response); err != nil {
+		return err
+	}
+	status, err := parseStatus(response.Status)
+	if err != nil {
+		return err
+	}
+	*e = Error{Status: status, SCIMType: response.SCIMType, Detail: response.Detail}
+	return nil
+}
+
+// parseStatus parses an HTTP status code, given as a string or a number.
+func parseStatus(status interface{}) (int, error) {
+	switch s := status.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid status: %q", s)
+		}
+		return i, nil
+	case float64:
+		if s != float64(int(s)) {
+			return 0, fmt.Errorf("invalid status: %v", s)
+		}
+		return int(s), nil
+	case int:
+		return s, nil
+	case int64:
+		return int(s), nil
+	default:
+		return 0, fmt.Errorf("invalid status: %v", s)
+	}
+}
+
+// unmarshal checks the schemas of the given message, unless the given schema is empty, and stores its attributes in
+// the given struct. The first rune of the names of the attributes is lowered, the way the marshal package looks them up,
+// which makes e.g. "Resources" and "Operations" match their fields.
+func unmarshal(data map[string]interface{}, schema string, v interface{}) error {
+	if schema != "" {
+		if err := checkSchemas(data, schema); err != nil {
+			return err
+		}
+	}
+	normalized := make(map[string]interface{}, len(data))
+	for k, value := range data {
+		if k != "" {
+			k = strings.ToLower(k[:1]) + k[1:]
+		}
+		normalized[k] = value
+	}
+	return marshal.Unmarshal(normalized, v)
+}
+
+// checkSchemas checks whether the schemas of the given message, if present, contain the given schema.
+func checkSchemas(data map[string]interface{}, schema string) error {
+	schemas, ok := data["schemas"]
+	if !ok {
+		return nil
+	}
+	if list, ok := schemas.([]interface{}); ok {
+		for _, s := range list {
+			if s, ok := s.(string); ok && strings.EqualFold(s, schema) {
+				return nil
+			}
+		}
+	}
+	if list, ok := schemas.([]string); ok {
+		for _, s := range list {
+			if strings.EqualFold(s, schema) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("schemas do not contain %s: %v", schema, schemas)
+}
+
+// marshalValue converts the given value into a value of a message. Resources, complex values and slices of them are
+// marshalled with marshal.Marshal, a time.Time becomes a dateTime string and the other values are kept as is.
+func marshalValue(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case nil, map[string]interface{}, string:
+		return value, nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(value), nil
+	case time.Time:
+		return value.Format(time.RFC3339), nil
+	case marshal.Marshaler:
+		return value.MarshalSCIM()
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return marshal.Marshal(v.Interface())
+	case reflect.Slice, reflect.Array:
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			element, err := marshalValue(v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			values[i] = element
+		}
+		return values, nil
+	default:
+		return v.Interface(), nil
+	}
+}
@@ -0,0 +1,237 @@
+package messages
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/scim2/tools/marshal"
+)
+
+type user struct {
+	ID       string `scim:"id"`
+	UserName string `scim:"userName"`
+	Name     struct {
+		GivenName string `scim:"givenName"`
+	} `scim:"name"`
+}
+
+// roundTrip marshals the given message, converts it to JSON and back, and unmarshals it into v.
+func roundTrip(t *testing.T, message marshal.Marshaler, v marshal.Unmarshaler) map[string]interface{} {
+	t.Helper()
+	data, err := marshal.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := marshal.Unmarshal(decoded, v); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func ExampleListResponse() {
+	response := ListResponse[map[string]interface{}]{
+		TotalResults: 1,
+		Resources: []map[string]interface{}{
+			{"userName": "di-wu"},
+		},
+	}
+	data, _ := marshal.Marshal(response)
+	raw, _ := json.Marshal(data)
+	fmt.Println(string(raw))
+	// Output:
+	// {"Resources":[{"userName":"di-wu"}],"schemas":["urn:ietf:params:scim:api:messages:2.0:ListResponse"],"totalResults":1}
+}
+
+func TestListResponse(t *testing.T) {
+	u := user{ID: "1", UserName: "di-wu"}
+	u.Name.GivenName = "Quint"
+	response := ListResponse[user]{TotalResults: 3, ItemsPerPage: 1, StartIndex: 2, Resources: []user{u}}
+
+	var decoded ListResponse[user]
+	data := roundTrip(t, response, &decoded)
+	if !reflect.DeepEqual(response, decoded) {
+		t.Errorf("expected %+v, got %+v", response, decoded)
+	}
+	if _, ok := data["Resources"]; !ok {
+		t.Error("expected Resources attribute")
+	}
+
+	var pointers ListResponse[*user]
+	roundTrip(t, response, &pointers)
+	if len(pointers.Resources) != 1 || *pointers.Resources[0] != u {
+		t.Errorf("expected %+v, got %+v", u, pointers.Resources)
+	}
+
+	if err := decoded.UnmarshalSCIM(map[string]interface{}{
+		"schemas": []interface{}{PatchOpSchema},
+	}); err == nil {
+		t.Error("error expected, got none")
+	}
+}
+
+func ExampleSearchRequest() {
+	count := 10
+	data, _ := marshal.Marshal(SearchRequest{
+		Attributes: []string{"userName"},
+		Filter:     `userName sw "d"`,
+		SortOrder:  Descending,
+		Count:      &count,
+	})
+	raw, _ := json.Marshal(data)
+	fmt.Println(string(raw))
+	// Output:
+	// {"attributes":["userName"],"count":10,"filter":"userName sw \"d\"","schemas":["urn:ietf:params:scim:api:messages:2.0:SearchRequest"],"sortOrder":"descending"}
+}
+
+func TestSearchRequest(t *testing.T) {
+	zero := 0
+	request := SearchRequest{
+		Attributes:         []string{"userName", "name.givenName"},
+		ExcludedAttributes: []string{"emails"},
+		Filter:             `userName eq "di-wu"`,
+		SortBy:             "userName",
+		SortOrder:          Ascending,
+		StartIndex:         2,
+		Count:              &zero,
+	}
+	var decoded SearchRequest
+	roundTrip(t, request, &decoded)
+	if !reflect.DeepEqual(request, decoded) {
+		t.Errorf("expected %+v, got %+v", request, decoded)
+	}
+}
+
+func ExamplePatchOp() {
+	data, _ := marshal.Marshal(PatchOp{
+		Operations: []PatchOperation{
+			{Op: Replace, Path: "userName", Value: "di-wu"},
+			{Op: Remove, Path: `emails[type eq "work"]`},
+		},
+	})
+	raw, _ := json.Marshal(data)
+	fmt.Println(string(raw))
+	// Output:
+	// {"Operations":[{"op":"replace","path":"userName","value":"di-wu"},{"op":"remove","path":"emails[type eq \"work\"]"}],"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"]}
+}
+
+func TestPatchOp(t *testing.T) {
+	u := user{UserName: "di-wu"}
+	var decoded PatchOp
+	roundTrip(t, PatchOp{Operations: []PatchOperation{
+		{Op: Add, Value: u},
+		{Op: Replace, Path: "emails", Value: []map[string]interface{}{{"value": "quint@example.com"}}},
+	}}, &decoded)
+	expected := []PatchOperation{
+		{Op: Add, Value: map[string]interface{}{"userName": "di-wu"}},
+		{Op: Replace, Path: "emails", Value: []interface{}{map[string]interface{}{"value": "quint@example.com"}}},
+	}
+	if !reflect.DeepEqual(expected, decoded.Operations) {
+		t.Errorf("expected %+v, got %+v", expected, decoded.Operations)
+	}
+
+	// Operations are case insensitive.
+	if err := decoded.UnmarshalSCIM(map[string]interface{}{
+		"Operations": []interface{}{map[string]interface{}{"op": "Remove", "path": "title"}},
+	}); err != nil || decoded.Operations[0].Op != Remove {
+		t.Errorf("unexpected result: %+v, %v", decoded, err)
+	}
+
+	for _, test := range []struct {
+		op       map[string]interface{}
+		scimType string
+	}{
+		{map[string]interface{}{"op": "add"}, InvalidSyntax},
+		{map[string]interface{}{"op": "replace", "path": "title"}, InvalidSyntax},
+		{map[string]interface{}{"op": "add", "path": "title", "value": nil}, InvalidSyntax},
+		{map[string]interface{}{"op": "remove"}, NoTarget},
+		{map[string]interface{}{"op": "move", "path": "title"}, InvalidSyntax},
+	} {
+		var e *Error
+		if err := decoded.UnmarshalSCIM(map[string]interface{}{
+			"schemas":    []interface{}{PatchOpSchema},
+			"Operations": []interface{}{test.op},
+		}); !errors.As(err, &e) || e.SCIMType != test.scimType {
+			t.Errorf("%s error expected for %v, got %v", test.scimType, test.op, err)
+		}
+	}
+	if err := decoded.UnmarshalSCIM(map[string]interface{}{"Operations": []interface{}{}}); err == nil {
+		t.Error("error expected for a request without operations, got none")
+	}
+}
+
+func TestBulk(t *testing.T) {
+	request := BulkRequest{
+		FailOnErrors: 1,
+		Operations: []BulkOperation{
+			{Method: "POST", BulkID: "qwerty", Path: "/Users", Data: map[string]interface{}{"userName": "di-wu"}},
+			{Method: "DELETE", Path: "/Users/1", Version: `W/"1"`},
+		},
+	}
+	var decodedRequest BulkRequest
+	roundTrip(t, request, &decodedRequest)
+	if !reflect.DeepEqual(request, decodedRequest) {
+		t.Errorf("expected %+v, got %+v", request, decodedRequest)
+	}
+
+	response := BulkResponse{
+		Operations: []BulkResponseOperation{
+			{Method: "POST", BulkID: "qwerty", Location: "/Users/2", Status: 201},
+			{Method: "DELETE", Location: "/Users/1", Status: 404, Response: Error{Status: 404, Detail: "not found"}},
+		},
+	}
+	var decodedResponse BulkResponse
+	roundTrip(t, response, &decodedResponse)
+	if ops := decodedResponse.Operations; len(ops) != 2 || ops[0].Status != 201 || ops[1].Location != "/Users/1" {
+		t.Errorf("unexpected operations: %+v", ops)
+	}
+	if e, err := decodedResponse.Operations[0].ErrorResponse(); e != nil || err != nil {
+		t.Errorf("unexpected error response: %v, %v", e, err)
+	}
+	e, err := decodedResponse.Operations[1].ErrorResponse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *e != (Error{Status: 404, Detail: "not found"}) {
+		t.Errorf("unexpected error response: %+v", e)
+	}
+}
+
+func ExampleError() {
+	data, _ := marshal.Marshal(Error{Status: 400, SCIMType: InvalidFilter, Detail: "invalid filter"})
+	raw, _ := json.Marshal(data)
+	fmt.Println(string(raw))
+	// Output:
+	// {"detail":"invalid filter","schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"scimType":"invalidFilter","status":"400"}
+}
+
+func TestError(t *testing.T) {
+	for _, status := range []interface{}{"409", 409.0} {
+		var e Error
+		if err := e.UnmarshalSCIM(map[string]interface{}{
+			"schemas":  []interface{}{ErrorSchema},
+			"status":   status,
+			"scimType": Uniqueness,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if e != (Error{Status: 409, SCIMType: Uniqueness}) {
+			t.Errorf("unexpected error: %+v", e)
+		}
+	}
+
+	var e Error
+	if err := e.UnmarshalSCIM(map[string]interface{}{"status": "conflict"}); err == nil {
+		t.Error("error expected, got none")
+	}
+}
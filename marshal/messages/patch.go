@@ -0,0 +1,102 @@
+package messages
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/scim2/tools/marshal"
+)
+
+// The operations of a PATCH request.
+const (
+	Add     = "add"
+	Remove  = "remove"
+	Replace = "replace"
+)
+
+// PatchOp is the body of a PATCH request.
+type PatchOp struct {
+	Operations []PatchOperation
+}
+
+// PatchOperation is an operation of a PATCH request. The value can be a simple value, a resource (or complex value)
+// that is supported by the marshal package or a slice of those. Unmarshalled values are kept as is, e.g. a complex
+// value is a map[string]interface{}.
+type PatchOperation struct {
+	Op    string
+	Path  string
+	Value interface{}
+}
+
+func (p PatchOp) MarshalSCIM() (map[string]interface{}, error) {
+	operations := make([]interface{}, len(p.Operations))
+	for i, op := range p.Operations {
+		operation := map[string]interface{}{
+			"op": op.Op,
+		}
+		if op.Path != "" {
+			operation["path"] = op.Path
+		}
+		if op.Value != nil {
+			value, err := marshalValue(op.Value)
+			if err != nil {
+				return nil, fmt.Errorf("operation %d: %v", i, err)
+			}
+			operation["value"] = value
+		}
+		operations[i] = operation
+	}
+	return map[string]interface{}{
+		"schemas":    []interface{}{PatchOpSchema},
+		"Operations": operations,
+	}, nil
+}
+
+// UnmarshalSCIM fills the PatchOp with the given data. The operations are lower cased, the values of "add" and
+// "replace" operations are required (and can not be null) and so is the path of a "remove" operation. A request
+// without operations or with an invalid operation results in an *Error with the SCIM type "invalidSyntax", or
+// "noTarget" for a "remove" operation without a path.
+func (p *PatchOp) UnmarshalSCIM(data map[string]interface{}) error {
+	var request struct {
+		Operations []map[string]interface{}
+	}
+	if err := unmarshal(data, PatchOpSchema, &request); err != nil {
+		return err
+	}
+	if len(request.Operations) == 0 {
+		return invalidPatch(InvalidSyntax, "request does not contain any operations")
+	}
+
+	operations := make([]PatchOperation, len(request.Operations))
+	for i, op := range request.Operations {
+		var operation struct {
+			Op    string
+			Path  string
+			Value interface{}
+		}
+		if err := unmarshal(op, "", &operation); err != nil {
+			return fmt.Errorf("operation %d: %v", i, err)
+		}
+		switch operation.Op = strings.ToLower(operation.Op); operation.Op {
+		case Add, Replace:
+			if operation.Value == nil || operation.Value == marshal.Null {
+				return invalidPatch(InvalidSyntax, "operation %d: %s operation without a value", i, operation.Op)
+			}
+		case Remove:
+			if operation.Path == "" {
+				return invalidPatch(NoTarget, "operation %d: remove operation without a path", i)
+			}
+		default:
+			return invalidPatch(InvalidSyntax, "operation %d: invalid operation: %q", i, operation.Op)
+		}
+		operations[i] = PatchOperation(operation)
+	}
+	*p = PatchOp{Operations: operations}
+	return nil
+}
+
+// invalidPatch returns the error of an invalid PATCH request with the given SCIM type.
+func invalidPatch(scimType, format string, a ...interface{}) error {
+	return &Error{Status: http.StatusBadRequest, SCIMType: scimType, Detail: fmt.Sprintf(format, a...)}
+}
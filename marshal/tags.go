@@ -12,6 +12,7 @@ type tag struct {
 	indexes     []int
 	allowZero   bool
 	ignore      bool
+	inline      bool
 	sub         *tag
 }
 
@@ -67,6 +68,10 @@ func parseTags(field reflect.StructField) tag {
 				} else {
 					t.sub.ignore = true
 				}
+			case "inline":
+				if !sub {
+					t.inline = true
+				}
 			}
 
 			if strings.HasPrefix(option, "index=") || strings.HasPrefix(option, "i=") {
@@ -119,6 +124,15 @@ func parseTags(field reflect.StructField) tag {
 	return t
 }
 
+// path returns the name of the attribute, including the name of the sub attribute if present.
+// e.g. "name" or "name/givenName"
+func (t tag) path() string {
+	if t.sub == nil {
+		return t.name
+	}
+	return t.name + "/" + t.sub.path()
+}
+
 func (t tag) all() bool {
 	return len(t.indexes) == 1 && t.indexes[0] == -1
 }
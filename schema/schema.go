@@ -35,27 +35,33 @@ var (
 				Description: "The name of the resource type of the resource.",
 				Mutability:  ReadOnly,
 				Name:        "resourceType",
+				Type:        StringType,
 			},
 			{
 				Description: "The DateTime that the resource was added to the service provider.",
 				Mutability:  ReadOnly,
 				Name:        "created",
+				Type:        DateTimeType,
 			},
 			{
 				Description: "The most recent DateTime that the details of this resource were updated at the service provider.",
 				Mutability:  ReadOnly,
 				Name:        "lastModified",
+				Type:        DateTimeType,
 			},
 			{
-				Description: "The URI of the resource being returned.",
-				Mutability:  ReadOnly,
-				Name:        "location",
+				Description:    "The URI of the resource being returned.",
+				Mutability:     ReadOnly,
+				Name:           "location",
+				Type:           ReferenceType,
+				ReferenceTypes: []string{"uri"},
 			},
 			{
 				CaseExact:   true,
 				Description: "The version of the resource being returned.",
 				Mutability:  ReadOnly,
 				Name:        "version",
+				Type:        StringType,
 			},
 		},
 	}
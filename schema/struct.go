@@ -0,0 +1,289 @@
+package schema
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// referenceTyper is implemented by the reference types of the struct generator.
+type referenceTyper interface {
+	ReferenceTypes() []string
+}
+
+// FromStruct derives a ReferenceSchema from the given struct (or pointer to a struct). The name of the schema is the
+// name of the struct, the id and description have to be set by the caller.
+//
+// Every exported field becomes an attribute, named after the "scim" tag or the field name with a lower case first
+// letter. Fields of embedded structs are promoted (a struct that embeds itself is followed once), fields with the
+// "ignore" or "!" option are skipped. The common attributes (schemas, id, externalId and meta) and extensions (names
+// that contain a colon) are not part of the schema.
+//
+// The type of the attribute is derived from the type of the field: strings (and encoding.TextMarshaler) are strings,
+// time.Time is a dateTime, []byte is binary and structs are complex. Slices are multi valued. Types with a
+// ReferenceTypes method are references. The other characteristics are set with the following options of the "scim"
+// tag, the description is taken from the "description" tag:
+//
+//	required
+//	caseExact
+//	mutability=readOnly|readWrite|immutable|writeOnly
+//	returned=always|never|default|request
+//	uniqueness=none|server|global
+//	canonical=value1;value2
+//	referenceTypes=User;Group (implies type=reference)
+//	type=reference|binary|dateTime (only for string fields)
+//
+// e.g.
+//
+//	type User struct {
+//		UserName string `scim:"userName,required,uniqueness=server"`
+//		Title    string `scim:"title" description:"The user's title, such as \"Vice President.\""`
+//	}
+func FromStruct(v interface{}) (ReferenceSchema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return ReferenceSchema{}, fmt.Errorf("expected a struct, got %v", reflect.TypeOf(v))
+	}
+
+	attributes, err := structAttributes(t, false)
+	if err != nil {
+		return ReferenceSchema{}, err
+	}
+	return ReferenceSchema{
+		Name:       t.Name(),
+		Attributes: attributes,
+	}, nil
+}
+
+// structAttributes returns the attributes of the fields of the given struct type.
+func structAttributes(t reflect.Type, sub bool) ([]*Attribute, error) {
+	attributes := make([]*Attribute, 0, t.NumField())
+	names := make(map[string]bool)
+	if err := collectAttributes(t, sub, map[reflect.Type]bool{}, names, &attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// collectAttributes appends the attributes of the fields of the given struct type, including the fields of embedded
+// structs. Embedded structs that (indirectly) embed themselves are only visited once.
+func collectAttributes(t reflect.Type, sub bool, visited map[reflect.Type]bool, names map[string]bool, attributes *[]*Attribute) error {
+	if visited[t] {
+		return nil
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, options := parseTag(f)
+		if options["ignore"] != "" || options["!"] != "" {
+			continue
+		}
+
+		if f.Anonymous && strings.Split(f.Tag.Get("scim"), ",")[0] == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && ft != timeType {
+				if err := collectAttributes(ft, sub, visited, names, attributes); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		if strings.Contains(name, "/") {
+			return fmt.Errorf("%s: sub attribute paths are not supported", name)
+		}
+		if strings.Contains(name, ":") || (!sub && isCoreAttribute(name)) {
+			continue
+		}
+		if names[strings.ToLower(name)] {
+			return fmt.Errorf("duplicate attribute: %s", name)
+		}
+		names[strings.ToLower(name)] = true
+
+		attribute, err := fieldAttribute(f, name, options, sub)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		*attributes = append(*attributes, attribute)
+	}
+	return nil
+}
+
+// fieldAttribute returns the attribute of the given field.
+func fieldAttribute(f reflect.StructField, name string, options map[string]string, sub bool) (*Attribute, error) {
+	attribute := &Attribute{
+		Name:        name,
+		Description: f.Tag.Get("description"),
+		Mutability:  ReadWrite,
+		Returned:    Default,
+		Uniqueness:  None,
+	}
+
+	t := indirect(f.Type)
+	if (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && !isBinary(t) && !t.Implements(textMarshalerType) {
+		attribute.MultiValued = true
+		t = indirect(t.Elem())
+	}
+	if options["mV"] != "" || options["multiValued"] != "" {
+		attribute.MultiValued = true
+	}
+
+	typ, err := attributeType(t)
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := reflect.Zero(t).Interface().(referenceTyper); ok && typ == StringType {
+		typ, attribute.ReferenceTypes = ReferenceType, r.ReferenceTypes()
+	}
+	if v, ok := options["type"]; ok {
+		switch Type(v) {
+		case typ:
+		case ReferenceType, BinaryType, DateTimeType, StringType:
+			if typ != StringType {
+				return nil, fmt.Errorf("type %q does not match %s", v, t)
+			}
+			typ = Type(v)
+		default:
+			return nil, fmt.Errorf("type %q does not match %s", v, t)
+		}
+	}
+	attribute.Type = typ
+
+	if typ == ComplexType {
+		if sub {
+			return nil, fmt.Errorf("complex attributes can not contain complex sub attributes")
+		}
+		if attribute.SubAttributes, err = structAttributes(t, true); err != nil {
+			return nil, err
+		}
+	}
+
+	for k, v := range options {
+		switch k {
+		case "required":
+			attribute.Required = true
+		case "caseExact":
+			attribute.CaseExact = true
+		case "mutability":
+			switch m := Mutability(v); m {
+			case ReadOnly, ReadWrite, Immutable, WriteOnly:
+				attribute.Mutability = m
+			default:
+				return nil, fmt.Errorf("invalid mutability: %q", v)
+			}
+		case "returned":
+			switch r := Returned(v); r {
+			case Always, Never, Default, Request:
+				attribute.Returned = r
+			default:
+				return nil, fmt.Errorf("invalid returned: %q", v)
+			}
+		case "uniqueness":
+			switch u := Uniqueness(v); u {
+			case None, Server, Global:
+				attribute.Uniqueness = u
+			default:
+				return nil, fmt.Errorf("invalid uniqueness: %q", v)
+			}
+		case "canonical":
+			attribute.CanonicalValues = strings.Split(v, ";")
+		case "referenceTypes":
+			attribute.ReferenceTypes = strings.Split(v, ";")
+		}
+	}
+	if attribute.ReferenceTypes != nil && attribute.Type != ReferenceType {
+		if attribute.Type != StringType {
+			return nil, fmt.Errorf("reference types of a %s attribute", attribute.Type)
+		}
+		attribute.Type = ReferenceType
+	}
+	return attribute, nil
+}
+
+// attributeType returns the type of the attribute that represents a value of the given go type.
+func attributeType(t reflect.Type) (Type, error) {
+	switch {
+	case t == timeType:
+		return DateTimeType, nil
+	case isBinary(t):
+		return BinaryType, nil
+	case t.Implements(textMarshalerType):
+		return StringType, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return StringType, nil
+	case reflect.Bool:
+		return BooleanType, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return IntegerType, nil
+	case reflect.Float32, reflect.Float64:
+		return DecimalType, nil
+	case reflect.Struct:
+		return ComplexType, nil
+	default:
+		return "", fmt.Errorf("unsupported type: %s", t)
+	}
+}
+
+// parseTag returns the name of the attribute of the given field and the options of its "scim" tag. Options without a
+// value, e.g. "required", have their own name as value.
+func parseTag(f reflect.StructField) (string, map[string]string) {
+	parts := strings.Split(f.Tag.Get("scim"), ",")
+	name := parts[0]
+	if name == "" {
+		name = strings.ToLower(f.Name[:1]) + f.Name[1:]
+	}
+	options := make(map[string]string)
+	for _, option := range parts[1:] {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) == 2 {
+			options[kv[0]] = kv[1]
+		} else {
+			options[kv[0]] = kv[0]
+		}
+	}
+	return name, options
+}
+
+// isCoreAttribute checks whether the given name is the name of one of the common attributes.
+func isCoreAttribute(name string) bool {
+	for _, attribute := range CoreAttributes {
+		if strings.EqualFold(attribute.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinary checks whether the given type is a byte slice.
+func isBinary(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// indirect returns the type the given (pointer) type points to.
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,204 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/scim2/tools/schema"
+)
+
+type Name struct {
+	GivenName  string `scim:"givenName"`
+	FamilyName string `scim:"familyName"`
+}
+
+type Email struct {
+	Value   string `scim:"value" description:"Email address for the User."`
+	Type    string `scim:"type,canonical=work;home;other"`
+	Primary bool
+}
+
+type Resource struct {
+	Schemas []string `scim:"schemas"`
+	ID      string   `scim:"id"`
+}
+
+type User struct {
+	Resource
+
+	UserName     string     `scim:"userName,required,uniqueness=server" description:"Unique identifier for the User."`
+	Name         *Name      `scim:"name"`
+	Emails       []Email    `scim:"emails"`
+	Password     string     `scim:"password,mutability=writeOnly,returned=never"`
+	Active       bool       `scim:"active"`
+	LastLogin    *time.Time `scim:"lastLogin,mutability=readOnly"`
+	Photo        []byte     `scim:"photo"`
+	Manager      string     `scim:"manager,referenceTypes=User"`
+	Internal     string     `scim:"internal,!"`
+	internalNote string
+}
+
+func ExampleFromStruct() {
+	s, _ := schema.FromStruct(User{})
+	s.ID = "urn:example:User"
+	raw, _ := json.MarshalIndent(s.Attributes[1], "", "  ")
+	fmt.Println(s.Name, len(s.Attributes))
+	fmt.Println(string(raw))
+	for _, attr := range s.Attributes {
+		fmt.Println(attr.Name, attr.Type, attr.MultiValued)
+	}
+	// Output:
+	// User 8
+	// {
+	//   "name": "name",
+	//   "type": "complex",
+	//   "subAttributes": [
+	//     {
+	//       "name": "givenName",
+	//       "type": "string",
+	//       "multiValued": false,
+	//       "required": false,
+	//       "caseExact": false,
+	//       "mutability": "readWrite",
+	//       "returned": "default",
+	//       "uniqueness": "none",
+	//       "referenceTypes": null
+	//     },
+	//     {
+	//       "name": "familyName",
+	//       "type": "string",
+	//       "multiValued": false,
+	//       "required": false,
+	//       "caseExact": false,
+	//       "mutability": "readWrite",
+	//       "returned": "default",
+	//       "uniqueness": "none",
+	//       "referenceTypes": null
+	//     }
+	//   ],
+	//   "multiValued": false,
+	//   "required": false,
+	//   "caseExact": false,
+	//   "mutability": "readWrite",
+	//   "returned": "default",
+	//   "uniqueness": "none",
+	//   "referenceTypes": null
+	// }
+	// userName string false
+	// name complex false
+	// emails complex true
+	// password string false
+	// active boolean false
+	// lastLogin dateTime false
+	// photo binary false
+	// manager reference false
+}
+
+func TestFromStruct(t *testing.T) {
+	s, err := schema.FromStruct(&User{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := make(map[string]*schema.Attribute)
+	for _, attr := range s.Attributes {
+		attrs[attr.Name] = attr
+	}
+
+	if a := attrs["userName"]; !a.Required || a.Uniqueness != schema.Server || a.Description != "Unique identifier for the User." {
+		t.Errorf("unexpected userName: %+v", a)
+	}
+	if a := attrs["password"]; a.Mutability != schema.WriteOnly || a.Returned != schema.Never {
+		t.Errorf("unexpected password: %+v", a)
+	}
+	if a := attrs["lastLogin"]; a.Mutability != schema.ReadOnly {
+		t.Errorf("unexpected lastLogin: %+v", a)
+	}
+	if a := attrs["manager"]; len(a.ReferenceTypes) != 1 || a.ReferenceTypes[0] != "User" {
+		t.Errorf("unexpected manager: %+v", a)
+	}
+	emailType := attrs["emails"].SubAttributes[1]
+	if len(emailType.CanonicalValues) != 3 || emailType.CanonicalValues[2] != "other" {
+		t.Errorf("unexpected emails.type: %+v", emailType)
+	}
+	if primary := attrs["emails"].SubAttributes[2]; primary.Name != "primary" || primary.Type != schema.BooleanType {
+		t.Errorf("unexpected emails.primary: %+v", primary)
+	}
+}
+
+type Loop struct {
+	*Loop
+	UserName string `scim:"userName"`
+}
+
+type Outer struct {
+	*Inner
+	UserName string `scim:"userName"`
+}
+
+type Inner struct {
+	*Outer
+	DisplayName string `scim:"displayName"`
+}
+
+func TestFromStruct_recursive(t *testing.T) {
+	for _, test := range []struct {
+		v     interface{}
+		names []string
+	}{
+		{Loop{}, []string{"userName"}},
+		{Outer{}, []string{"displayName", "userName"}},
+	} {
+		s, err := schema.FromStruct(test.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, attr := range s.Attributes {
+			names = append(names, attr.Name)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(test.names) {
+			t.Errorf("%T: unexpected attributes: %v", test.v, names)
+		}
+	}
+}
+
+func TestFromStruct_invalid(t *testing.T) {
+	for _, v := range []interface{}{
+		nil,
+		"user",
+		struct {
+			Name struct{ Given struct{ First string } }
+		}{},
+		struct {
+			Count int `scim:"count,type=reference"`
+		}{},
+		struct {
+			Mode string `scim:"mode,mutability=readWriteOnly"`
+		}{},
+		struct {
+			Returned string `scim:"returned,returned=sometimes"`
+		}{},
+		struct {
+			Unique string `scim:"unique,uniqueness=always"`
+		}{},
+		struct {
+			Active bool `scim:"active,referenceTypes=User"`
+		}{},
+		struct {
+			Attributes map[string]string
+		}{},
+		struct {
+			Name      string `scim:"name"`
+			OtherName string `scim:"Name"`
+		}{},
+		struct {
+			GivenName string `scim:"name/givenName"`
+		}{},
+	} {
+		if _, err := schema.FromStruct(v); err == nil {
+			t.Errorf("expected an error for %T", v)
+		}
+	}
+}